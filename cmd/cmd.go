@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+)
+
+// command 表示一个命令行子命令
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+// commands 注册所有可用的子命令
+var commands = map[string]command{
+	"jwt-key":          {usage: "管理 JWT 签名密钥 (generate | rotate | list)", run: runJwtKey},
+	"import-markdown":  {usage: "从 Markdown 压缩包 (Hexo/Hugo/Jekyll) 导入文章，默认试运行", run: runImportMarkdown},
+	"import-wordpress": {usage: "从 WordPress WXR 文件导入文章、评论和作者，默认试运行", run: runImportWordPress},
+	"export":           {usage: "导出全站内容 (Markdown 文章、分类、标签、评论和媒体文件) 为 ZIP 压缩包", run: runExport},
+	"build-site":       {usage: "将公开文章生成为可独立部署的静态 HTML 站点", run: runBuildSite},
+}
+
+// Execute 根据命令行参数执行子命令。
+// 没有参数时返回 false，由调用方继续启动 HTTP 服务。
+func Execute(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		printUsage()
+		os.Exit(2)
+	}
+
+	if err := cmd.run(args[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", args[0], err)
+		os.Exit(1)
+	}
+	return true
+}
+
+// printUsage 打印所有子命令的说明
+func printUsage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(os.Stderr, "用法: Go-Blog [command] [args]")
+	fmt.Fprintln(os.Stderr, "不带参数时启动博客服务。可用命令:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", name, commands[name].usage)
+	}
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"flag"
+	"fmt"
+	"goblog/model"
+	"os"
+)
+
+// runExport 处理 export 子命令
+//
+//	export [-media] [-o goblog-export.zip]
+//
+// 将全站内容导出为 ZIP 压缩包，压缩包中的 Markdown 文章可通过 import-markdown 重新导入
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	output := fs.String("o", "goblog-export.zip", "输出文件")
+	media := fs.Bool("media", false, "下载上传到对象存储的图片并打包")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	model.InitDb()
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	manifest, err := model.ExportArchive(file, model.ExportOptions{Media: *media})
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(*output)
+		return err
+	}
+
+	fmt.Printf("已导出到 %s: 文章 %d 篇，分类 %d 个，标签 %d 个，系列 %d 个，评论 %d 条，媒体文件 %d 个\n",
+		*output, manifest.Articles, manifest.Categories, manifest.Tags, manifest.Series, manifest.Comments, manifest.Media)
+	printMissingMedia(manifest.MissingMedia)
+	return nil
+}
+
+// runBuildSite 处理 build-site 子命令
+//
+//	build-site [-media] 输出目录
+//
+// 将公开文章渲染为静态 HTML 站点，目录可直接部署到任意静态托管服务
+func runBuildSite(args []string) error {
+	fs := flag.NewFlagSet("build-site", flag.ContinueOnError)
+	media := fs.Bool("media", false, "下载上传到对象存储的图片并改为相对路径")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("用法: build-site [-media] 输出目录")
+	}
+
+	model.InitDb()
+	report, err := model.BuildSite(fs.Arg(0), model.ExportOptions{Media: *media})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("已生成到 %s: 文章 %d 篇，页面 %d 个，媒体文件 %d 个\n", fs.Arg(0), report.Articles, report.Pages, report.Media)
+	printMissingMedia(report.MissingMedia)
+	return nil
+}
+
+// printMissingMedia 列出下载失败的媒体文件
+func printMissingMedia(missing []string) {
+	for _, u := range missing {
+		fmt.Printf("  警告: 无法下载 %s，保留原地址\n", u)
+	}
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"archive/zip"
+	"errors"
+	"flag"
+	"fmt"
+	"goblog/model"
+	"os"
+)
+
+// importFlags 是导入子命令的公共参数
+type importFlags struct {
+	author   uint
+	category string
+	apply    bool
+	file     string
+}
+
+// parseImportFlags 解析导入子命令的参数
+func parseImportFlags(name string, args []string) (*importFlags, error) {
+	f := &importFlags{}
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.UintVar(&f.author, "author", 0, "导入文章的作者 (用户 ID)")
+	fs.StringVar(&f.category, "category", "", "没有分类的文章归入该分类")
+	fs.BoolVar(&f.apply, "apply", false, "执行导入 (默认只试运行)")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() != 1 {
+		return nil, fmt.Errorf("用法: %s -author 1 [-category 名称] [-apply] 文件", name)
+	}
+	if f.author == 0 {
+		return nil, errors.New("请通过 -author 指定作者")
+	}
+	f.file = fs.Arg(0)
+	return f, nil
+}
+
+// options 连接数据库并返回导入参数
+func (f *importFlags) options() model.ImportOptions {
+	model.InitDb()
+	if f.apply {
+		model.InitRedis() // 导入后需要让服务端的缓存失效
+	}
+	return model.ImportOptions{
+		AuthorID:        f.author,
+		DefaultCategory: f.category,
+		DryRun:          !f.apply,
+	}
+}
+
+// runImportMarkdown 处理 import-markdown 子命令
+//
+//	import-markdown -author 1 [-category 默认分类] [-apply] blog.zip
+//
+// 默认只试运行并打印报告，确认无误后加 -apply 执行导入
+func runImportMarkdown(args []string) error {
+	f, err := parseImportFlags("import-markdown", args)
+	if err != nil {
+		return err
+	}
+	zr, err := zip.OpenReader(f.file)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	report, err := model.ImportMarkdownZip(&zr.Reader, f.options())
+	if err != nil {
+		return err
+	}
+	printImportReport(report)
+	return nil
+}
+
+// runImportWordPress 处理 import-wordpress 子命令
+//
+//	import-wordpress -author 1 [-category 默认分类] [-apply] wordpress.xml
+//
+// 默认只试运行并打印报告。可以重复执行，已导入的文章和评论会被跳过
+func runImportWordPress(args []string) error {
+	f, err := parseImportFlags("import-wordpress", args)
+	if err != nil {
+		return err
+	}
+	file, err := os.Open(f.file)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	report, err := model.ImportWXR(file, f.options())
+	if err != nil {
+		return err
+	}
+	printImportReport(report)
+	return nil
+}
+
+// printImportReport 打印导入报告
+func printImportReport(report *model.ImportReport) {
+	for _, item := range report.Items {
+		fmt.Printf("%-6s %s -> %s [%s]", item.Action, item.Source, item.Slug, item.Category)
+		if item.Comments > 0 {
+			fmt.Printf(" 评论 %d 条", item.Comments)
+		}
+		if item.Message != "" {
+			fmt.Printf(" %s", item.Message)
+		}
+		fmt.Println()
+		for _, w := range item.Warnings {
+			fmt.Printf("       警告: %s\n", w)
+		}
+	}
+
+	mode := "导入完成"
+	if report.DryRun {
+		mode = "试运行 (未写入)，确认无误后加 -apply 执行导入"
+	}
+	fmt.Printf("\n%s: 共 %d 篇，新建 %d，跳过 %d，失败 %d\n", mode, report.Total, report.Created, report.Skipped, report.Failed)
+	if len(report.NewCategories) > 0 {
+		fmt.Printf("新分类: %v\n", report.NewCategories)
+	}
+	if len(report.NewAuthors) > 0 {
+		fmt.Printf("新作者: %v (密码随机生成，需由管理员重置)\n", report.NewAuthors)
+	}
+}
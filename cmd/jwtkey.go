@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"goblog/middleware"
+	"goblog/utils"
+	"os"
+)
+
+// runJwtKey 处理 jwt-key 子命令
+//
+//	jwt-key generate [-alg RS256] [-file path]  创建新的密钥文件 (已存在时拒绝覆盖)
+//	jwt-key rotate   [-alg RS256] [-keep 3]     生成新密钥并设为当前签发密钥，旧密钥保留用于校验
+//	jwt-key list                                 列出密钥文件中的所有密钥
+func runJwtKey(args []string) error {
+	if len(args) == 0 {
+		return errors.New("缺少操作，可选 generate、rotate、list")
+	}
+
+	fs := flag.NewFlagSet("jwt-key "+args[0], flag.ContinueOnError)
+	alg := fs.String("alg", utils.JwtAlg, "签名算法: HS256、RS256 或 EdDSA")
+	file := fs.String("file", utils.JwtKeyFile, "密钥文件路径")
+	keep := fs.Int("keep", 3, "rotate 时保留的密钥数量 (包括新密钥)，0 表示全部保留")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "generate":
+		if _, err := os.Stat(*file); err == nil {
+			return fmt.Errorf("密钥文件 %s 已存在，请使用 rotate", *file)
+		}
+		set := &middleware.KeySet{}
+		k, err := middleware.RotateKeySet(set, *alg, 0)
+		if err != nil {
+			return err
+		}
+		if err := middleware.SaveKeySet(*file, set); err != nil {
+			return err
+		}
+		fmt.Printf("已生成密钥 %s (%s)，写入 %s\n", k.Kid, k.Alg, *file)
+
+	case "rotate":
+		set, err := middleware.LoadKeySet(*file)
+		if err != nil {
+			return err
+		}
+		k, err := middleware.RotateKeySet(set, *alg, *keep)
+		if err != nil {
+			return err
+		}
+		if err := middleware.SaveKeySet(*file, set); err != nil {
+			return err
+		}
+		fmt.Printf("已轮换至新密钥 %s (%s)，当前共保留 %d 把密钥，重启服务后生效\n", k.Kid, k.Alg, len(set.Keys))
+
+	case "list":
+		set, err := middleware.LoadKeySet(*file)
+		if err != nil {
+			return err
+		}
+		for _, k := range set.Keys {
+			mark := " "
+			if k.Kid == set.Active {
+				mark = "*"
+			}
+			fmt.Printf("%s %-24s %-6s %s\n", mark, k.Kid, k.Alg, k.CreatedAt.Format(utils.TimeFormatSecond))
+		}
+
+	default:
+		return fmt.Errorf("未知操作 %s，可选 generate、rotate、list", args[0])
+	}
+	return nil
+}
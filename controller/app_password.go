@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"goblog/dto"
+	"goblog/model"
+	"goblog/utils/errmsg"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAppPasswords 查询当前用户的应用密码
+// @Router /api/v1/profile/app-passwords [get]
+func GetAppPasswords(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	passwords, err := model.GetAppPasswords(userID.(uint))
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.SUCCESS.Status,
+		"data":    passwords,
+		"message": errmsg.SUCCESS.Message,
+	})
+}
+
+// AddAppPassword 为当前用户生成应用密码，明文密码只在本次响应中返回
+// @Router /api/v1/profile/app-passwords [post]
+func AddAppPassword(c *gin.Context) {
+	var req dto.ReqAppPassword
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errmsg.BindError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	appPassword, password, err := model.CreateAppPassword(userID.(uint), req.Name)
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": errmsg.CreateAppPwdSuccess.Status,
+		"data": gin.H{
+			"appPassword": appPassword,
+			"password":    password,
+		},
+		"message": errmsg.CreateAppPwdSuccess.Message,
+	})
+}
+
+// DeleteAppPassword 撤销当前用户的应用密码
+// @Router /api/v1/profile/app-passwords/{id} [delete]
+func DeleteAppPassword(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		appErr := errmsg.ErrInvalidAppPwdID
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if err := model.DeleteAppPassword(userID.(uint), uint(id)); err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.DeleteAppPwdSuccess.Status,
+		"message": errmsg.DeleteAppPwdSuccess.Message,
+	})
+}
@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"goblog/dto"
+	"goblog/model"
+	"goblog/utils/errmsg"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetArchives 获取按年月分组的文章归档
+// @Router /api/v1/archives [get]
+func GetArchives(c *gin.Context) {
+	archives, err := model.GetArchives()
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.SUCCESS.Status,
+		"data":    archives,
+		"message": errmsg.SUCCESS.Message,
+	})
+}
+
+// GetArchiveArticles 获取某年某月发布的文章
+// @Router /api/v1/archives/{year}/{month} [get]
+func GetArchiveArticles(c *gin.Context) {
+	year, err := strconv.Atoi(c.Param("year"))
+	if err != nil {
+		appErr := errmsg.ErrInvalidArchive
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+	month, err := strconv.Atoi(c.Param("month"))
+	if err != nil {
+		appErr := errmsg.ErrInvalidArchive
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	var req dto.ReqFindArchive
+	if err := c.ShouldBindQuery(&req); err != nil {
+		appErr := errmsg.BindError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	if req.PageNum <= 0 {
+		req.PageNum = 1
+	}
+
+	articles, total, err := model.GetArchiveArticles(currentViewer(c), year, month, req.PageSize, req.PageNum)
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.SUCCESS.Status,
+		"data":    articles,
+		"total":   total,
+		"message": errmsg.SUCCESS.Message,
+	})
+}
@@ -4,6 +4,7 @@
 	"goblog/dto"
 	"goblog/model"
 	"goblog/utils/errmsg"
+	"log"
 	"net/http"
 	"strconv"
 
@@ -21,18 +22,32 @@ func AddArticle(c *gin.Context) {
 	}
 
 	newArticle := &model.Article{
-		Title:   req.Title,
-		Cid:     req.Cid,
-		Desc:    req.Desc,
-		Content: req.Content,
-		Img:     req.Img,
+		Title:       req.Title,
+		Slug:        req.Slug,
+		Cid:         req.Cid,
+		Desc:        req.Desc,
+		Content:     req.Content,
+		Img:         req.Img,
+		Tags:        newTags(req.Tags),
+		Pinned:      req.Pinned,
+		Featured:    req.Featured,
+		SortWeight:  req.SortWeight,
+		Visibility:  req.Visibility,
+		VisibleRole: req.VisibleRole,
+		Password:    req.Password,
+		Draft:       req.Draft,
 	}
 
-	if err := model.CreateArticle(newArticle); err != nil {
+	userID, _ := c.Get("userID")
+	if err := model.CreateArticle(newArticle, userID.(uint)); err != nil {
 		appErr := errmsg.FromError(err)
 		c.JSON(appErr.HTTPStatus, appErr)
 		return
 	}
+	// 发布后丢弃新文章的自动保存
+	if err := model.DiscardAutosave(userID.(uint), 0); err != nil {
+		log.Printf("丢弃用户 %d 的自动保存失败: %v", userID, err)
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"status":  errmsg.CreateArticleSuccess.Status,
@@ -50,13 +65,45 @@ func GetArticleInfo(c *gin.Context) {
 		return
 	}
 
-	article, err := model.GetArticleInfo(uint(id))
+	respondArticle(c, uint(id))
+}
+
+// GetArticleBySlug 通过 slug 获取文章详细信息，旧 slug 会 301 跳转到当前地址
+// @Router /api/v1/articles/slug/{slug} [get]
+func GetArticleBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+	id, err := model.FindArticleIDBySlug(slug)
+	if err == errmsg.ErrArticleNotExist {
+		if current, err := model.FindSlugRedirect(model.SlugTargetArticle, slug); err == nil {
+			c.Redirect(http.StatusMovedPermanently, "/api/v1/articles/slug/"+current)
+			return
+		}
+	}
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	respondArticle(c, id)
+}
+
+// respondArticle 返回访问者可见的文章详情并记录一次阅读
+func respondArticle(c *gin.Context, id uint) {
+	unlock, _ := c.Cookie(unlockCookieName(id))
+	article, err := model.GetArticleInfo(id, currentViewer(c), unlock)
 	if err != nil {
 		appErr := errmsg.FromError(err)
 		c.JSON(appErr.HTTPStatus, appErr)
 		return
 	}
 
+	// 阅读量统计失败不影响文章的正常返回
+	if err := model.RecordView(article.ID, model.VisitorID(c.ClientIP(), c.Request.UserAgent())); err != nil {
+		log.Printf("记录文章 %d 阅读量失败: %v", article.ID, err)
+	}
+
+	setETag(c, article.Version)
 	c.JSON(http.StatusOK, gin.H{
 		"status":  errmsg.SUCCESS.Status,
 		"data":    article,
@@ -81,7 +128,7 @@ func GetArticle(c *gin.Context) {
 		req.PageNum = 1
 	}
 
-	articles, total, err := model.GetArticles(req.Title, req.PageSize, req.PageNum)
+	articles, total, err := model.GetArticles(currentViewer(c), req.Title, req.Sort, req.PageSize, req.PageNum)
 	if err != nil {
 		appErr := errmsg.FromError(err)
 		c.JSON(appErr.HTTPStatus, appErr)
@@ -96,6 +143,100 @@ func GetArticle(c *gin.Context) {
 	})
 }
 
+// GetPopularArticles 获取热门文章排行
+// @Router /api/v1/articles/popular [get]
+func GetPopularArticles(c *gin.Context) {
+	var req dto.ReqPopular
+	if err := c.ShouldBindQuery(&req); err != nil {
+		appErr := errmsg.BindError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	if req.Period == "" {
+		req.Period = "week"
+	}
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+
+	articles, err := model.GetPopularArticles(req.Period, req.Limit)
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.SUCCESS.Status,
+		"data":    articles,
+		"message": errmsg.SUCCESS.Message,
+	})
+}
+
+// GetFeaturedArticles 获取精选文章 (首页轮播)
+// @Router /api/v1/articles/featured [get]
+func GetFeaturedArticles(c *gin.Context) {
+	var req dto.ReqFeatured
+	if err := c.ShouldBindQuery(&req); err != nil {
+		appErr := errmsg.BindError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	if req.Limit <= 0 {
+		req.Limit = 5
+	}
+
+	articles, err := model.GetFeaturedArticles(req.Limit)
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.SUCCESS.Status,
+		"data":    articles,
+		"message": errmsg.SUCCESS.Message,
+	})
+}
+
+// GetRelatedArticles 获取相关文章推荐
+// @Router /api/v1/articles/{id}/related [get]
+func GetRelatedArticles(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		appErr := errmsg.ErrInvalidArticleID
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	var req dto.ReqRelated
+	if err := c.ShouldBindQuery(&req); err != nil {
+		appErr := errmsg.BindError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	if req.Limit <= 0 {
+		req.Limit = 5
+	}
+
+	articles, err := model.GetRelatedArticles(uint(id), req.Limit)
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.SUCCESS.Status,
+		"data":    articles,
+		"message": errmsg.SUCCESS.Message,
+	})
+}
+
 // EditArticle 编辑文章
 // @Router /api/v1/articles/{id} [put]
 func EditArticle(c *gin.Context) {
@@ -113,24 +254,41 @@ func EditArticle(c *gin.Context) {
 		return
 	}
 
+	version, ifMatch, appErr := editVersion(c, req.Version)
+	if appErr != nil {
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
 	articleToUpdate := &model.Article{
-		Title:   req.Title,
-		Cid:     req.Cid,
-		Desc:    req.Desc,
-		Content: req.Content,
-		Img:     req.Img,
+		Title:       req.Title,
+		Slug:        req.Slug,
+		Cid:         req.Cid,
+		Desc:        req.Desc,
+		Content:     req.Content,
+		Img:         req.Img,
+		Tags:        newTags(req.Tags),
+		Pinned:      req.Pinned,
+		Featured:    req.Featured,
+		SortWeight:  req.SortWeight,
+		Visibility:  req.Visibility,
+		VisibleRole: req.VisibleRole,
+		Password:    req.Password,
+		Draft:       req.Draft,
+		Version:     version,
 	}
 
 	if err := model.EditArticle(uint(id), articleToUpdate); err != nil {
-		appErr := errmsg.FromError(err)
-		c.JSON(appErr.HTTPStatus, appErr)
+		respondEditError(c, err, ifMatch)
 		return
 	}
+	// 保存后丢弃该文章的自动保存
+	userID, _ := c.Get("userID")
+	if err := model.DiscardAutosave(userID.(uint), uint(id)); err != nil {
+		log.Printf("丢弃用户 %d 对文章 %d 的自动保存失败: %v", userID, id, err)
+	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status":  errmsg.UpdateArticleSuccess.Status,
-		"message": errmsg.UpdateArticleSuccess.Message,
-	})
+	respondEdited(c, errmsg.UpdateArticleSuccess, articleToUpdate.Version)
 }
 
 // DeleteArticle 删除文章
@@ -165,7 +323,8 @@ func GetCommentsByArticleId(c *gin.Context) {
 		return
 	}
 
-	comments, err := model.GetCommentsByArticleId(uint(id))
+	unlock, _ := c.Cookie(unlockCookieName(uint(id)))
+	comments, err := model.GetCommentsByArticleId(uint(id), currentViewer(c), unlock)
 	if err != nil {
 		appErr := errmsg.FromError(err)
 		c.JSON(appErr.HTTPStatus, appErr)
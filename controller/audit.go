@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"goblog/dto"
+	"goblog/model"
+	"goblog/utils/errmsg"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAuditLogs 查询管理操作的审计日志
+// @Router /api/v1/audit-logs [get]
+func GetAuditLogs(c *gin.Context) {
+	var req dto.ReqFindAuditLog
+	if err := c.ShouldBindQuery(&req); err != nil {
+		appErr := errmsg.BindError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	if req.PageNum <= 0 {
+		req.PageNum = 1
+	}
+
+	logs, total, err := model.GetAuditLogs(req.Action, req.UserID, req.PageSize, req.PageNum)
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.SUCCESS.Status,
+		"data":    logs,
+		"total":   total,
+		"message": errmsg.SUCCESS.Message,
+	})
+}
@@ -35,7 +35,7 @@ func Register(c *gin.Context) {
 		Email:    req.Email,
 	}
 
-	if err := model.RegisterUser(newUser); err != nil {
+	if err := model.RegisterUser(newUser, req.InviteCode); err != nil {
 		appErr := errmsg.FromError(err)
 		c.JSON(appErr.HTTPStatus, appErr)
 		return
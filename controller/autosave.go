@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"goblog/dto"
+	"goblog/model"
+	"goblog/utils/errmsg"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// autosaveArticleID 解析自动保存对应的文章 ID，新文章为 0
+func autosaveArticleID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		appErr := errmsg.ErrInvalidArticleID
+		c.JSON(appErr.HTTPStatus, appErr)
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// GetAutosave 获取当前用户对某文章最新的自动保存，打开编辑器时调用
+// @Router /api/v1/articles/{id}/autosave [get]
+func GetAutosave(c *gin.Context) {
+	id, ok := autosaveArticleID(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	data, err := model.GetAutosave(userID.(uint), id)
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.SUCCESS.Status,
+		"data":    data,
+		"message": errmsg.SUCCESS.Message,
+	})
+}
+
+// SaveAutosave 自动保存编辑器状态，覆盖上一次的自动保存，不产生文章修订
+// @Router /api/v1/articles/{id}/autosave [put]
+func SaveAutosave(c *gin.Context) {
+	id, ok := autosaveArticleID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ReqAutosave
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errmsg.BindError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	data := &model.Autosave{
+		UserID:      userID.(uint),
+		ArticleID:   id,
+		BaseVersion: req.BaseVersion,
+		Title:       req.Title,
+		Cid:         req.Cid,
+		Desc:        req.Desc,
+		Content:     req.Content,
+		Img:         req.Img,
+		Tags:        req.Tags,
+	}
+	if err := model.SaveAutosave(data); err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.AutosaveSuccess.Status,
+		"data":    gin.H{"savedAt": data.SavedAt},
+		"message": errmsg.AutosaveSuccess.Message,
+	})
+}
+
+// DiscardAutosave 丢弃当前用户对某文章的自动保存
+// @Router /api/v1/articles/{id}/autosave [delete]
+func DiscardAutosave(c *gin.Context) {
+	id, ok := autosaveArticleID(c)
+	if !ok {
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	if err := model.DiscardAutosave(userID.(uint), id); err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.DiscardDraftSuccess.Status,
+		"message": errmsg.DiscardDraftSuccess.Message,
+	})
+}
@@ -0,0 +1,41 @@
+package controller
+
+import (
+	"goblog/dto"
+	"goblog/model"
+	"goblog/utils/errmsg"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BulkArticles 批量修改分类、添加/移除标签、发布/撤回、移入/恢复回收站，返回每篇文章的结果
+// @Router /api/v1/articles/bulk [post]
+func BulkArticles(c *gin.Context) {
+	var req dto.ReqBulkArticles
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errmsg.BindError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	report, err := model.BulkArticles(userID.(uint), &model.BulkArticleOp{
+		Op:     req.Op,
+		IDs:    req.IDs,
+		Cid:    req.Cid,
+		Tags:   req.Tags,
+		Atomic: req.Atomic,
+	})
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.SUCCESS.Status,
+		"data":    report,
+		"message": errmsg.SUCCESS.Message,
+	})
+}
@@ -56,6 +56,7 @@ func AddCategory(c *gin.Context) {
 
 	newCategory := &model.Category{
 		Name: req.Name,
+		Slug: req.Slug,
 	}
 
 	if err := model.CreateCategory(newCategory); err != nil {
@@ -88,20 +89,24 @@ func EditCategory(c *gin.Context) {
 		return
 	}
 
+	version, ifMatch, appErr := editVersion(c, req.Version)
+	if appErr != nil {
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
 	categoryToUpdate := &model.Category{
-		Name: req.Name,
+		Name:    req.Name,
+		Slug:    req.Slug,
+		Version: version,
 	}
 
 	if err := model.EditCategory(uint(id), categoryToUpdate); err != nil {
-		appErr := errmsg.FromError(err)
-		c.JSON(appErr.HTTPStatus, appErr)
+		respondEditError(c, err, ifMatch)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"status":  errmsg.UpdateCategorySuccess.Status,
-		"message": errmsg.UpdateCategorySuccess.Message,
-	})
+	respondEdited(c, errmsg.UpdateCategorySuccess, categoryToUpdate.Version)
 }
 
 // DeleteCategory 删除分类
@@ -144,6 +149,32 @@ func FindCategoryById(c *gin.Context) {
 		return
 	}
 
+	setETag(c, category.Version)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.SUCCESS.Status,
+		"data":    category,
+		"message": errmsg.SUCCESS.Message,
+	})
+}
+
+// FindCategoryBySlug 通过 slug 查找分类，旧 slug 会 301 跳转到当前地址
+// @Router /api/v1/categories/slug/{slug} [get]
+func FindCategoryBySlug(c *gin.Context) {
+	slug := c.Param("slug")
+	category, err := model.FindCategoryBySlug(slug)
+	if err == errmsg.ErrCateNotExist {
+		if current, err := model.FindSlugRedirect(model.SlugTargetCategory, slug); err == nil {
+			c.Redirect(http.StatusMovedPermanently, "/api/v1/categories/slug/"+current)
+			return
+		}
+	}
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	setETag(c, category.Version)
 	c.JSON(http.StatusOK, gin.H{
 		"status":  errmsg.SUCCESS.Status,
 		"data":    category,
@@ -175,7 +206,7 @@ func GetCateArticle(c *gin.Context) {
 		req.PageNum = 1
 	}
 
-	articles, total, err := model.GetCateArticle(uint(id), req.PageSize, req.PageNum)
+	articles, total, err := model.GetCateArticle(currentViewer(c), uint(id), req.Sort, req.PageSize, req.PageNum)
 	if err != nil {
 		appErr := errmsg.FromError(err)
 		c.JSON(appErr.HTTPStatus, appErr)
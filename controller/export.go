@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"fmt"
+	"goblog/dto"
+	"goblog/model"
+	"goblog/utils/errmsg"
+	"io"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sendExport 将导出结果写入临时文件后作为附件返回，生成失败时返回错误信息而不是不完整的压缩包
+func sendExport(c *gin.Context, prefix string, export func(w io.Writer, opts model.ExportOptions) error) {
+	var req dto.ReqExport
+	if err := c.ShouldBindQuery(&req); err != nil {
+		appErr := errmsg.BindError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	file, err := os.CreateTemp("", prefix+"-*.zip")
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	if err := export(file, model.ExportOptions{Media: req.Media}); err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+	c.FileAttachment(file.Name(), fmt.Sprintf("%s-%s.zip", prefix, time.Now().Format("20060102")))
+}
+
+// ExportArchive 导出全站内容 (Markdown 文章、分类、标签、系列、评论和可选的媒体文件) 为 ZIP 压缩包
+// @Router /api/v1/export [get]
+func ExportArchive(c *gin.Context) {
+	sendExport(c, "goblog-export", func(w io.Writer, opts model.ExportOptions) error {
+		_, err := model.ExportArchive(w, opts)
+		return err
+	})
+}
+
+// ExportSite 将公开文章渲染为静态 HTML 站点，打包为 ZIP 压缩包
+// @Router /api/v1/export/site [get]
+func ExportSite(c *gin.Context) {
+	sendExport(c, "goblog-site", func(w io.Writer, opts model.ExportOptions) error {
+		_, err := model.BuildSiteArchive(w, opts)
+		return err
+	})
+}
@@ -0,0 +1,69 @@
+package controller
+
+import (
+	"bytes"
+	"goblog/model"
+	"goblog/utils/errmsg"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSiteFeed 获取全站订阅源
+// @Router /api/v1/feeds/{format} [get]
+func GetSiteFeed(c *gin.Context) {
+	serveFeed(c, model.FeedScope{Kind: model.FeedScopeSite})
+}
+
+// GetCategoryFeed 获取分类订阅源
+// @Router /api/v1/feeds/categories/{id}/{format} [get]
+func GetCategoryFeed(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		appErr := errmsg.ErrInvalidCategoryID
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	serveFeed(c, model.FeedScope{Kind: model.FeedScopeCategory, ID: uint(id)})
+}
+
+// GetTagFeed 获取标签订阅源
+// @Router /api/v1/feeds/tags/{name}/{format} [get]
+func GetTagFeed(c *gin.Context) {
+	serveFeed(c, model.FeedScope{Kind: model.FeedScopeTag, Tag: c.Param("name")})
+}
+
+// GetAuthorFeed 获取作者订阅源
+// @Router /api/v1/feeds/authors/{id}/{format} [get]
+func GetAuthorFeed(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		appErr := errmsg.ErrInvalidUserID
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	serveFeed(c, model.FeedScope{Kind: model.FeedScopeAuthor, ID: uint(id)})
+}
+
+// serveFeed 输出指定范围的订阅源，格式取自路径参数 format
+func serveFeed(c *gin.Context, scope model.FeedScope) {
+	doc, err := model.GetFeed(scope, c.Param("format"))
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	serveDocument(c, doc)
+}
+
+// serveDocument 输出缓存的文档，由 http.ServeContent 处理 If-None-Match / If-Modified-Since 条件请求
+func serveDocument(c *gin.Context, doc *model.Document) {
+	c.Header("Content-Type", doc.ContentType)
+	c.Header("ETag", doc.ETag)
+	c.Header("Cache-Control", "public, max-age=300")
+	http.ServeContent(c.Writer, c.Request, "", doc.LastModified, bytes.NewReader(doc.Body))
+}
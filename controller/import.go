@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"archive/zip"
+	"goblog/dto"
+	"goblog/model"
+	"goblog/utils/errmsg"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// importRequest 解析导入请求的参数和上传的文件，失败时已写入响应
+func importRequest(c *gin.Context) (multipart.File, int64, model.ImportOptions, bool) {
+	var req dto.ReqImport
+	if err := c.ShouldBind(&req); err != nil {
+		appErr := errmsg.BindError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return nil, 0, model.ImportOptions{}, false
+	}
+
+	file, fileHeader, err := c.Request.FormFile("file")
+	if err != nil {
+		appErr := errmsg.ErrGetFileFailed
+		c.JSON(appErr.HTTPStatus, appErr)
+		return nil, 0, model.ImportOptions{}, false
+	}
+	if fileHeader.Size > model.ImportMaxSize {
+		file.Close()
+		appErr := errmsg.ErrImportTooLarge
+		c.JSON(appErr.HTTPStatus, appErr)
+		return nil, 0, model.ImportOptions{}, false
+	}
+
+	userID, _ := c.Get("userID")
+	opts := model.ImportOptions{
+		AuthorID:        userID.(uint),
+		DefaultCategory: req.Category,
+		DryRun:          req.DryRun == nil || *req.DryRun,
+	}
+	return file, fileHeader.Size, opts, true
+}
+
+// respondImport 返回导入报告
+func respondImport(c *gin.Context, report *model.ImportReport, err error) {
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.SUCCESS.Status,
+		"data":    report,
+		"message": errmsg.SUCCESS.Message,
+	})
+}
+
+// ImportMarkdown 从 Markdown 压缩包 (Hexo/Hugo/Jekyll) 导入文章，默认只试运行并返回报告
+// @Router /api/v1/import/markdown [post]
+func ImportMarkdown(c *gin.Context) {
+	file, size, opts, ok := importRequest(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	zr, err := zip.NewReader(file, size)
+	if err != nil {
+		appErr := errmsg.ErrImportFile
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+	report, err := model.ImportMarkdownZip(zr, opts)
+	respondImport(c, report, err)
+}
+
+// ImportWordPress 从 WordPress 导出的 WXR 文件导入文章、评论和作者，默认只试运行并返回报告
+// @Router /api/v1/import/wordpress [post]
+func ImportWordPress(c *gin.Context) {
+	file, _, opts, ok := importRequest(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	report, err := model.ImportWXR(file, opts)
+	respondImport(c, report, err)
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"goblog/dto"
+	"goblog/model"
+	"goblog/utils/errmsg"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AddInvitation 生成邀请码
+// @Router /api/v1/invitations [post]
+func AddInvitation(c *gin.Context) {
+	var req dto.ReqInvite
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errmsg.BindError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	invitation := &model.Invitation{
+		CreatedBy: userID.(uint),
+		MaxUses:   req.MaxUses,
+	}
+	if invitation.MaxUses == 0 {
+		invitation.MaxUses = 1
+	}
+	if req.ExpireHours > 0 {
+		expiresAt := time.Now().Add(time.Duration(req.ExpireHours) * time.Hour)
+		invitation.ExpiresAt = &expiresAt
+	}
+
+	if err := model.CreateInvitation(invitation); err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.CreateInviteSuccess.Status,
+		"data":    invitation,
+		"message": errmsg.CreateInviteSuccess.Message,
+	})
+}
+
+// GetInvitations 查询邀请码列表
+// @Router /api/v1/invitations [get]
+func GetInvitations(c *gin.Context) {
+	var req dto.ReqFindInvite
+	if err := c.ShouldBindQuery(&req); err != nil {
+		appErr := errmsg.BindError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	if req.PageNum <= 0 {
+		req.PageNum = 1
+	}
+
+	invitations, total, err := model.GetInvitations(req.PageSize, req.PageNum)
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.SUCCESS.Status,
+		"data":    invitations,
+		"total":   total,
+		"message": errmsg.SUCCESS.Message,
+	})
+}
+
+// DeleteInvitation 作废邀请码
+// @Router /api/v1/invitations/{id} [delete]
+func DeleteInvitation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		appErr := errmsg.ErrInvalidInviteID
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	if err := model.DeleteInvitation(uint(id)); err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.DeleteInviteSuccess.Status,
+		"message": errmsg.DeleteInviteSuccess.Message,
+	})
+}
@@ -0,0 +1,23 @@
+package controller
+
+import (
+	"goblog/utils/errmsg"
+	"goblog/utils/markdown"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetHighlightCSS 获取文章代码高亮的样式表
+// @Router /api/v1/markdown/highlight.css [get]
+func GetHighlightCSS(c *gin.Context) {
+	css, err := markdown.HighlightCSS()
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=86400")
+	c.Data(http.StatusOK, "text/css; charset=utf-8", []byte(css))
+}
@@ -0,0 +1,344 @@
+package controller
+
+import (
+	"bytes"
+	"goblog/model"
+	"goblog/utils"
+	"goblog/utils/errmsg"
+	"goblog/utils/xmlrpc"
+	"log"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	xmlrpcMaxBody      = 32 << 20 // 请求体 (包含 base64 编码的媒体文件)
+	mediaObjectMaxSize = 20 << 20 // 单个媒体文件
+	recentPostsMax     = 100      // getRecentPosts 最多返回的文章数
+	metaWeblogBlogID   = "1"      // 站点只有一个博客
+)
+
+// rpcMethod 是一个 XML-RPC 方法。auth 为用户名参数的位置，密码紧随其后
+type rpcMethod struct {
+	auth int
+	call func(u *model.User, args *xmlrpc.Args) (any, error)
+}
+
+// rpcMethods 是支持的 MetaWeblog 和 Blogger API 方法，参数顺序与规范一致
+var rpcMethods map[string]rpcMethod
+
+func init() {
+	rpcMethods = map[string]rpcMethod{
+		"blogger.getUsersBlogs":     {auth: 1, call: getUsersBlogs},
+		"blogger.deletePost":        {auth: 2, call: deletePost},
+		"metaWeblog.getUsersBlogs":  {auth: 1, call: getUsersBlogs},
+		"metaWeblog.deletePost":     {auth: 2, call: deletePost},
+		"metaWeblog.getCategories":  {auth: 1, call: getCategories},
+		"metaWeblog.newPost":        {auth: 1, call: newPost},
+		"metaWeblog.editPost":       {auth: 1, call: editPost},
+		"metaWeblog.getPost":        {auth: 1, call: getPost},
+		"metaWeblog.getRecentPosts": {auth: 1, call: getRecentPosts},
+		"metaWeblog.newMediaObject": {auth: 1, call: newMediaObject},
+	}
+}
+
+// XMLRPC 处理 MetaWeblog / Blogger API 的 XML-RPC 调用，使用用户名和应用密码认证
+// @Router /api/v1/xmlrpc [post]
+func XMLRPC(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, xmlrpcMaxBody)
+	method, params, err := xmlrpc.ParseCall(c.Request.Body)
+	if err != nil {
+		writeRPC(c, nil, err)
+		return
+	}
+
+	if method == "system.listMethods" {
+		names := make([]string, 0, len(rpcMethods)+1)
+		for name := range rpcMethods {
+			names = append(names, name)
+		}
+		names = append(names, "system.listMethods")
+		sort.Strings(names)
+		writeRPC(c, names, nil)
+		return
+	}
+	m, ok := rpcMethods[method]
+	if !ok {
+		writeRPC(c, nil, &xmlrpc.Fault{Code: xmlrpc.FaultMethodNotFound, Message: "不支持的方法 " + method})
+		return
+	}
+
+	args := xmlrpc.NewArgs(params)
+	username, password := args.String(m.auth), args.String(m.auth+1)
+	if err := args.Err(); err != nil {
+		writeRPC(c, nil, err)
+		return
+	}
+	user, err := model.CheckAppPassword(username, password)
+	if err != nil {
+		writeRPC(c, nil, err)
+		return
+	}
+
+	result, err := m.call(user, args)
+	if err == nil {
+		err = args.Err()
+	}
+	writeRPC(c, result, err)
+}
+
+// writeRPC 写入 XML-RPC 响应。XML-RPC 的错误也以 200 状态码返回，业务错误的 faultCode 为对应的 HTTP 状态码
+func writeRPC(c *gin.Context, result any, err error) {
+	if err == nil {
+		body, encErr := xmlrpc.Response(result)
+		if encErr == nil {
+			c.Data(http.StatusOK, xmlrpc.ContentType, body)
+			return
+		}
+		err = encErr
+	}
+
+	fault, ok := err.(*xmlrpc.Fault)
+	if !ok {
+		appErr := errmsg.FromError(err)
+		if appErr.HTTPStatus >= http.StatusInternalServerError {
+			log.Printf("XML-RPC 调用失败: %v", err)
+		}
+		fault = &xmlrpc.Fault{Code: appErr.HTTPStatus, Message: appErr.Message}
+	}
+	c.Data(http.StatusOK, xmlrpc.ContentType, xmlrpc.FaultResponse(fault))
+}
+
+// postID 将客户端传递的文章 ID 转换为数字
+func postID(s string) (uint, error) {
+	id, err := strconv.ParseUint(strings.TrimSpace(s), 10, 0)
+	if err != nil || id == 0 {
+		return 0, errmsg.ErrInvalidArticleID
+	}
+	return uint(id), nil
+}
+
+// getUsersBlogs 返回用户可以发布文章的博客，站点只有一个博客
+// blogger.getUsersBlogs(appkey, username, password)
+func getUsersBlogs(u *model.User, _ *xmlrpc.Args) (any, error) {
+	return []any{map[string]any{
+		"blogid":   metaWeblogBlogID,
+		"blogName": utils.SiteTitle,
+		"url":      utils.SiteURL + "/",
+		"xmlrpc":   utils.SiteAPI + "/xmlrpc",
+		"isAdmin":  u.Role == model.RoleAdmin,
+	}}, nil
+}
+
+// getCategories 返回所有分类
+// metaWeblog.getCategories(blogid, username, password)
+func getCategories(_ *model.User, _ *xmlrpc.Args) (any, error) {
+	cates, _, err := model.GetCategory(-1, 1)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]any, 0, len(cates))
+	for _, cate := range cates {
+		id := strconv.FormatUint(uint64(cate.ID), 10)
+		result = append(result, map[string]any{
+			"categoryId":   id,
+			"categoryName": cate.Name,
+			"title":        cate.Name,
+			"description":  cate.Name,
+			"htmlUrl":      utils.SiteURL + "/categories/" + id,
+			"rssUrl":       utils.SiteAPI + "/feeds/categories/" + id + "/rss",
+		})
+	}
+	return result, nil
+}
+
+// newPost 发布文章，publish 为 false 时保存为草稿
+// metaWeblog.newPost(blogid, username, password, struct, publish)
+func newPost(u *model.User, args *xmlrpc.Args) (any, error) {
+	content, publish := args.Struct(3), args.Bool(4, true)
+	if err := args.Err(); err != nil {
+		return nil, err
+	}
+	post := &model.ClientPost{}
+	applyPostStruct(post, content, publish)
+
+	id, err := model.CreateClientPost(u, post)
+	if err != nil {
+		return nil, err
+	}
+	return strconv.FormatUint(uint64(id), 10), nil
+}
+
+// editPost 更新文章，结构体中没有的字段保持不变
+// metaWeblog.editPost(postid, username, password, struct, publish)
+func editPost(u *model.User, args *xmlrpc.Args) (any, error) {
+	id, err := postID(args.String(0))
+	if err != nil {
+		return nil, err
+	}
+	content, publish := args.Struct(3), args.Bool(4, true)
+	if err := args.Err(); err != nil {
+		return nil, err
+	}
+
+	post, err := model.GetClientPost(u, id)
+	if err != nil {
+		return nil, err
+	}
+	applyPostStruct(post, content, publish)
+	if err := model.EditClientPost(u, id, post); err != nil {
+		return nil, err
+	}
+	return true, nil
+}
+
+// getPost 返回单篇文章
+// metaWeblog.getPost(postid, username, password)
+func getPost(u *model.User, args *xmlrpc.Args) (any, error) {
+	id, err := postID(args.String(0))
+	if err != nil {
+		return nil, err
+	}
+	post, err := model.GetClientPost(u, id)
+	if err != nil {
+		return nil, err
+	}
+	return postStruct(post), nil
+}
+
+// getRecentPosts 返回用户最新的文章 (包括草稿)
+// metaWeblog.getRecentPosts(blogid, username, password, numberOfPosts)
+func getRecentPosts(u *model.User, args *xmlrpc.Args) (any, error) {
+	limit := args.Int(3, 10)
+	if limit <= 0 || limit > recentPostsMax {
+		limit = recentPostsMax
+	}
+	posts, err := model.GetRecentClientPosts(u, limit)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]any, 0, len(posts))
+	for i := range posts {
+		result = append(result, postStruct(&posts[i]))
+	}
+	return result, nil
+}
+
+// deletePost 将文章移入回收站
+// blogger.deletePost(appkey, postid, username, password, publish)
+func deletePost(u *model.User, args *xmlrpc.Args) (any, error) {
+	id, err := postID(args.String(1))
+	if err != nil {
+		return nil, err
+	}
+	if err := model.DeleteClientPost(u, id); err != nil {
+		return nil, err
+	}
+	return true, nil
+}
+
+// newMediaObject 将媒体文件上传到对象存储
+// metaWeblog.newMediaObject(blogid, username, password, struct{name, type, bits})
+func newMediaObject(_ *model.User, args *xmlrpc.Args) (any, error) {
+	file := args.Struct(3)
+	if err := args.Err(); err != nil {
+		return nil, err
+	}
+	bits, ok := file["bits"].([]byte)
+	if !ok || len(bits) == 0 {
+		return nil, &xmlrpc.Fault{Code: xmlrpc.FaultInvalidParams, Message: "缺少文件内容 bits"}
+	}
+	if len(bits) > mediaObjectMaxSize {
+		return nil, errmsg.ErrImportTooLarge.WithMsg("文件不能超过 %d MB", mediaObjectMaxSize>>20)
+	}
+
+	url, err := model.UploadFile(bytes.NewReader(bits), int64(len(bits)))
+	if err != nil {
+		return nil, err
+	}
+	name, _ := xmlrpc.Member(file, "name")
+	mime, _ := xmlrpc.Member(file, "type")
+	return map[string]any{"url": url, "file": path.Base(name), "type": mime}, nil
+}
+
+// applyPostStruct 将 MetaWeblog 的文章结构体写入 post，只覆盖结构体中出现的字段
+func applyPostStruct(post *model.ClientPost, s map[string]any, publish bool) {
+	if title, ok := xmlrpc.Member(s, "title"); ok {
+		post.Title = title
+	}
+	if content, ok := xmlrpc.Member(s, "description"); ok {
+		post.Content = content
+		// 部分客户端将 "阅读更多" 之后的内容放在 mt_text_more 中
+		if more, _ := xmlrpc.Member(s, "mt_text_more"); strings.TrimSpace(more) != "" {
+			post.Content += "\n\n<!--more-->\n\n" + more
+		}
+	}
+	if excerpt, ok := xmlrpc.Member(s, "mt_excerpt"); ok {
+		post.Desc = excerpt
+	}
+	if slug, ok := xmlrpc.Member(s, "wp_slug"); ok {
+		post.Slug = slug
+	} else if slug, ok := xmlrpc.Member(s, "mt_basename"); ok {
+		post.Slug = slug
+	}
+	if categories, ok := xmlrpc.Members(s, "categories"); ok {
+		post.Categories = categories
+	}
+	if keywords, ok := xmlrpc.Member(s, "mt_keywords"); ok {
+		post.Tags = post.Tags[:0]
+		for _, tag := range strings.Split(keywords, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				post.Tags = append(post.Tags, tag)
+			}
+		}
+	}
+	if date, ok := xmlrpc.TimeMember(s, "date_created_gmt"); ok {
+		post.CreatedAt = date
+	} else if date, ok := xmlrpc.TimeMember(s, "dateCreated"); ok {
+		post.CreatedAt = date
+	}
+
+	// post_status 优先于 publish 参数
+	switch status, _ := xmlrpc.Member(s, "post_status"); status {
+	case "draft", "pending":
+		post.Draft = true
+	case "publish":
+		post.Draft = false
+	default:
+		post.Draft = !publish
+	}
+}
+
+// postStruct 将文章转换为 MetaWeblog 的文章结构体
+func postStruct(p *model.ClientPost) map[string]any {
+	status := "publish"
+	if p.Draft {
+		status = "draft"
+	}
+	categories := p.Categories
+	if categories == nil {
+		categories = []string{}
+	}
+	return map[string]any{
+		"postid":            strconv.FormatUint(uint64(p.ID), 10),
+		"userid":            strconv.FormatUint(uint64(p.AuthorID), 10),
+		"title":             p.Title,
+		"description":       p.Content,
+		"mt_excerpt":        p.Desc,
+		"mt_keywords":       strings.Join(p.Tags, ","),
+		"wp_slug":           p.Slug,
+		"categories":        categories,
+		"dateCreated":       p.CreatedAt,
+		"date_created_gmt":  p.CreatedAt,
+		"date_modified":     p.UpdatedAt,
+		"date_modified_gmt": p.UpdatedAt,
+		"post_status":       status,
+		"link":              p.Link,
+		"permaLink":         p.Link,
+	}
+}
@@ -0,0 +1,370 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"goblog/model"
+	"goblog/utils"
+	"goblog/utils/errmsg"
+	"goblog/utils/micropub"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Micropub 请求体的限制，路由中解析表单中 access_token 的中间件使用相同的限制
+const (
+	MicropubMaxBody   = 32 << 20 // 请求体 (包含 multipart 上传的图片)
+	MicropubMaxMemory = 8 << 20  // multipart 表单在内存中缓存的大小，超出部分写入临时文件
+)
+
+// micropubUser 返回 Token 对应的当前用户
+func micropubUser(c *gin.Context) *model.User {
+	v := currentViewer(c)
+	user := &model.User{Role: v.Role}
+	user.ID = v.UserID
+	return user
+}
+
+// micropubError 以 Micropub 规定的格式 {"error", "error_description"} 返回错误
+func micropubError(c *gin.Context, err error) {
+	var mpErr *micropub.Error
+	if errors.As(err, &mpErr) {
+		c.JSON(http.StatusBadRequest, mpErr)
+		return
+	}
+
+	appErr := errmsg.FromError(err)
+	code := micropub.ErrInvalidRequest
+	switch {
+	case appErr.HTTPStatus == http.StatusUnauthorized:
+		code = micropub.ErrUnauthorized
+	case appErr.HTTPStatus == http.StatusForbidden:
+		code = micropub.ErrForbidden
+	case appErr.HTTPStatus >= http.StatusInternalServerError:
+		code = micropub.ErrServerError
+		log.Printf("Micropub 请求失败: %v", err)
+	}
+	c.JSON(appErr.HTTPStatus, &micropub.Error{Code: code, Description: appErr.Message})
+}
+
+// micropubPostURL 返回文章在前台的地址，Micropub 以该地址标识文章
+func micropubPostURL(id uint) string {
+	return fmt.Sprintf("%s/articles/%d", utils.SiteURL, id)
+}
+
+// micropubPostID 从文章地址中解析文章 ID
+func micropubPostID(rawURL string) (uint, error) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(rawURL), utils.SiteURL+"/articles/")
+	if !ok {
+		return 0, &micropub.Error{Code: micropub.ErrInvalidRequest, Description: "不是本站的文章地址: " + rawURL}
+	}
+	return postID(strings.TrimSuffix(rest, "/"))
+}
+
+// GetMicropub 处理 Micropub 查询: config、source、syndicate-to 和 category
+// @Router /api/v1/micropub [get]
+func GetMicropub(c *gin.Context) {
+	switch q := c.Query("q"); q {
+	case "config":
+		c.JSON(http.StatusOK, gin.H{
+			"media-endpoint": utils.SiteAPI + "/micropub/media",
+			"syndicate-to":   []any{},
+			"q":              []string{"config", "source", "syndicate-to", "category"},
+			"post-types": []gin.H{
+				{"type": "note", "name": "短文"},
+				{"type": "article", "name": "文章"},
+				{"type": "photo", "name": "图片"},
+			},
+		})
+	case "syndicate-to":
+		c.JSON(http.StatusOK, gin.H{"syndicate-to": []any{}})
+	case "category":
+		micropubCategories(c)
+	case "source":
+		micropubSource(c)
+	default:
+		micropubError(c, &micropub.Error{Code: micropub.ErrInvalidRequest, Description: "不支持的查询 q=" + q})
+	}
+}
+
+// micropubCategories 返回所有分类名，filter 参数按名称过滤
+func micropubCategories(c *gin.Context) {
+	cates, _, err := model.GetCategory(-1, 1)
+	if err != nil {
+		micropubError(c, err)
+		return
+	}
+	filter := strings.ToLower(c.Query("filter"))
+	names := make([]string, 0, len(cates))
+	for _, cate := range cates {
+		if strings.Contains(strings.ToLower(cate.Name), filter) {
+			names = append(names, cate.Name)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"categories": names})
+}
+
+// micropubSource 返回文章的属性，properties[] 参数指定只返回部分属性。
+// 未指定 url 时返回当前用户最新的文章列表 (包括草稿)，数量由 limit 参数指定
+func micropubSource(c *gin.Context) {
+	user := micropubUser(c)
+	names := c.QueryArray("properties[]")
+	if len(names) == 0 {
+		names = c.QueryArray("properties")
+	}
+
+	if c.Query("url") == "" {
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+		if limit <= 0 || limit > recentPostsMax {
+			limit = recentPostsMax
+		}
+		posts, err := model.GetRecentClientPosts(user, limit)
+		if err != nil {
+			micropubError(c, err)
+			return
+		}
+		items := make([]gin.H, 0, len(posts))
+		for i := range posts {
+			items = append(items, micropubSourceItem(&posts[i], names))
+		}
+		c.JSON(http.StatusOK, gin.H{"items": items})
+		return
+	}
+
+	id, err := micropubPostID(c.Query("url"))
+	if err != nil {
+		micropubError(c, err)
+		return
+	}
+	post, err := model.GetClientPost(user, id)
+	if err != nil {
+		micropubError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, micropubSourceItem(post, names))
+}
+
+// micropubSourceItem 将文章转换为 h-entry。category 的第一个值为文章分类，其余为标签
+func micropubSourceItem(p *model.ClientPost, names []string) gin.H {
+	status := "published"
+	if p.Draft {
+		status = "draft"
+	}
+	categories := make([]any, 0, len(p.Categories)+len(p.Tags))
+	for _, name := range append(slices.Clone(p.Categories), p.Tags...) {
+		categories = append(categories, name)
+	}
+	all := micropub.Properties{
+		"name":        {p.Title},
+		"content":     {p.Content},
+		"category":    categories,
+		"mp-slug":     {p.Slug},
+		"published":   {p.CreatedAt.Format(time.RFC3339)},
+		"updated":     {p.UpdatedAt.Format(time.RFC3339)},
+		"post-status": {status},
+		"url":         {p.Link},
+	}
+	if p.Desc != "" {
+		all["summary"] = []any{p.Desc}
+	}
+
+	if len(names) == 0 {
+		return gin.H{"type": []string{"h-entry"}, "properties": all}
+	}
+	props := micropub.Properties{}
+	for _, name := range names {
+		if vals, ok := all[name]; ok {
+			props[name] = vals
+		}
+	}
+	return gin.H{"properties": props}
+}
+
+// Micropub 处理 Micropub 的创建、更新、删除和恢复请求，使用 Bearer Token 认证
+// (Authorization 头，或表单中的 access_token 字段)
+// @Router /api/v1/micropub [post]
+func Micropub(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, MicropubMaxBody)
+	req, err := micropub.ParseRequest(c.Request, MicropubMaxMemory)
+	if err != nil {
+		micropubError(c, err)
+		return
+	}
+	user := micropubUser(c)
+
+	if req.Action == micropub.ActionCreate {
+		micropubCreate(c, user, req)
+		return
+	}
+
+	id, err := micropubPostID(req.URL)
+	if err != nil {
+		micropubError(c, err)
+		return
+	}
+	switch req.Action {
+	case micropub.ActionUpdate:
+		err = micropubUpdate(user, id, req)
+	case micropub.ActionDelete:
+		err = model.DeleteClientPost(user, id)
+	case micropub.ActionUndelete:
+		err = model.RestoreClientPost(user, id)
+	}
+	if err != nil {
+		micropubError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// micropubCreate 发布文章，multipart 请求中的 photo 文件先上传到对象存储
+func micropubCreate(c *gin.Context, user *model.User, req *micropub.Request) {
+	if form := c.Request.MultipartForm; form != nil {
+		for _, key := range []string{"photo", "photo[]"} {
+			for _, fh := range form.File[key] {
+				url, err := uploadFileHeader(fh)
+				if err != nil {
+					micropubError(c, err)
+					return
+				}
+				req.Properties["photo"] = append(req.Properties["photo"], url)
+			}
+		}
+	}
+
+	post := &model.ClientPost{}
+	if err := setMicropubProps(post, req.Properties, false); err != nil {
+		micropubError(c, err)
+		return
+	}
+	id, err := model.CreateClientPost(user, post)
+	if err != nil {
+		micropubError(c, err)
+		return
+	}
+	c.Header("Location", micropubPostURL(id))
+	c.Status(http.StatusCreated)
+}
+
+// micropubUpdate 按 replace、add、delete 的顺序更新文章
+func micropubUpdate(user *model.User, id uint, req *micropub.Request) error {
+	post, err := model.GetClientPost(user, id)
+	if err != nil {
+		return err
+	}
+	// category 的第一个值为文章分类，其余为标签，与 q=source 返回的顺序一致
+	post.Categories = append(post.Categories, post.Tags...)
+	post.Tags = nil
+
+	if err := setMicropubProps(post, req.Replace, false); err != nil {
+		return err
+	}
+	if err := setMicropubProps(post, req.Add, true); err != nil {
+		return err
+	}
+	for _, name := range req.DeleteAll {
+		switch name {
+		case "name":
+			post.Title = ""
+		case "content":
+			post.Content = ""
+		case "summary":
+			post.Desc = ""
+		case "mp-slug":
+			post.Slug = ""
+		case "category":
+			post.Categories = nil
+		}
+	}
+	if removed := req.Delete.Strings("category"); len(removed) > 0 {
+		post.Categories = slices.DeleteFunc(post.Categories, func(name string) bool {
+			return slices.Contains(removed, name)
+		})
+	}
+	return model.EditClientPost(user, id, post)
+}
+
+// setMicropubProps 将 h-entry 的属性写入 post，只覆盖出现的属性。
+// add 为 true 时 category 追加到原有分类之后；photo 总是以 Markdown 图片追加到正文末尾
+func setMicropubProps(post *model.ClientPost, props micropub.Properties, add bool) error {
+	if props.Has("name") {
+		post.Title = props.String("name")
+	}
+	if props.Has("content") {
+		post.Content = props.String("content")
+	}
+	if props.Has("summary") {
+		post.Desc = props.String("summary")
+	}
+	if props.Has("mp-slug") {
+		post.Slug = props.String("mp-slug")
+	}
+	if props.Has("category") {
+		if !add {
+			post.Categories = nil
+		}
+		post.Categories = append(post.Categories, props.Strings("category")...)
+	}
+	if props.Has("published") {
+		published, err := time.Parse(time.RFC3339, props.String("published"))
+		if err != nil {
+			return &micropub.Error{Code: micropub.ErrInvalidRequest, Description: "published 必须是 RFC 3339 格式的时间"}
+		}
+		post.CreatedAt = published
+	}
+	if props.Has("post-status") {
+		switch status := props.String("post-status"); status {
+		case "draft":
+			post.Draft = true
+		case "published":
+			post.Draft = false
+		default:
+			return &micropub.Error{Code: micropub.ErrInvalidRequest, Description: "不支持的 post-status: " + status}
+		}
+	}
+
+	for _, photo := range props["photo"] {
+		if url := micropub.Text(photo); url != "" {
+			post.Content = strings.TrimSpace(fmt.Sprintf("%s\n\n![%s](%s)", post.Content, micropub.Member(photo, "alt"), url))
+		}
+	}
+	return nil
+}
+
+// MicropubMedia 是 Micropub 的媒体接口，上传 file 字段中的文件，在 Location 头中返回文件地址
+// @Router /api/v1/micropub/media [post]
+func MicropubMedia(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, MicropubMaxBody)
+	fh, err := c.FormFile("file")
+	if err != nil {
+		micropubError(c, &micropub.Error{Code: micropub.ErrInvalidRequest, Description: "缺少上传的文件 file"})
+		return
+	}
+	url, err := uploadFileHeader(fh)
+	if err != nil {
+		micropubError(c, err)
+		return
+	}
+	c.Header("Location", url)
+	c.JSON(http.StatusCreated, gin.H{"url": url})
+}
+
+// uploadFileHeader 将 multipart 表单中的文件上传到对象存储
+func uploadFileHeader(fh *multipart.FileHeader) (string, error) {
+	if fh.Size > mediaObjectMaxSize {
+		return "", errmsg.ErrImportTooLarge.WithMsg("文件不能超过 %d MB", mediaObjectMaxSize>>20)
+	}
+	file, err := fh.Open()
+	if err != nil {
+		return "", errmsg.ErrGetFileFailed
+	}
+	defer file.Close()
+	return model.UploadFile(file, fh.Size)
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"goblog/model"
+	"goblog/utils/errmsg"
+	"goblog/utils/seo"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FrontIndexFile 是前台单页应用的入口页面
+const FrontIndexFile = "./static/front/dist/index.html"
+
+// RenderHomePage 渲染前台首页，注入站点元信息
+// @Router / [get]
+func RenderHomePage(c *gin.Context) {
+	page, err := model.HomePage()
+	renderPage(c, page, err)
+}
+
+// RenderArticlePage 渲染前台文章页，注入 OpenGraph、Twitter Card 和 BlogPosting 结构化数据
+// @Router /articles/{id} [get]
+func RenderArticlePage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		renderPage(c, nil, errmsg.ErrInvalidArticleID)
+		return
+	}
+
+	page, err := model.ArticlePage(uint(id))
+	renderPage(c, page, err)
+}
+
+// RenderCategoryPage 渲染前台分类页，注入分类元信息
+// @Router /categories/{id} [get]
+func RenderCategoryPage(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		renderPage(c, nil, errmsg.ErrInvalidCategoryID)
+		return
+	}
+
+	page, err := model.CategoryPage(uint(id))
+	renderPage(c, page, err)
+}
+
+// renderPage 输出注入了元信息的 index.html。
+// 页面不存在时仍返回原始 index.html (由前端显示 404 页面)，但状态码为 404；
+// 需要登录或密码的页面返回原始 index.html，由前端处理；其他错误不影响页面的正常访问，只记录日志
+func renderPage(c *gin.Context, page *seo.Page, err error) {
+	index, readErr := os.ReadFile(FrontIndexFile)
+	if readErr != nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	status := http.StatusOK
+	if err != nil {
+		switch errmsg.FromError(err).HTTPStatus {
+		case http.StatusNotFound, http.StatusBadRequest:
+			status = http.StatusNotFound
+		case http.StatusUnauthorized, http.StatusForbidden:
+		default:
+			log.Printf("渲染页面 %s 失败: %v", c.Request.URL.Path, err)
+		}
+		c.Data(status, "text/html; charset=utf-8", index)
+		return
+	}
+
+	body, err := seo.Inject(index, page)
+	if err != nil {
+		log.Printf("渲染页面 %s 失败: %v", c.Request.URL.Path, err)
+		body = index
+	}
+	c.Data(status, "text/html; charset=utf-8", body)
+}
@@ -21,6 +21,7 @@ func GetProfile(c *gin.Context) {
 		return
 	}
 
+	setETag(c, profile.Version)
 	c.JSON(http.StatusOK, gin.H{
 		"status":  errmsg.SUCCESS.Status,
 		"data":    profile,
@@ -40,15 +41,18 @@ func UpdateProfile(c *gin.Context) {
 		return
 	}
 
-	err := model.UpdateProfileByUserID(userID.(uint), &req)
-	if err != nil {
-		appErr := errmsg.FromError(err)
+	version, ifMatch, appErr := editVersion(c, req.Version)
+	if appErr != nil {
 		c.JSON(appErr.HTTPStatus, appErr)
 		return
 	}
+	req.Version = version
 
-	c.JSON(http.StatusOK, gin.H{
-		"status":  errmsg.UpdateProfileSuccess.Status,
-		"message": errmsg.UpdateProfileSuccess.Message,
-	})
+	version, err := model.UpdateProfileByUserID(userID.(uint), &req)
+	if err != nil {
+		respondEditError(c, err, ifMatch)
+		return
+	}
+
+	respondEdited(c, errmsg.UpdateProfileSuccess, version)
 }
@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"goblog/dto"
+	"goblog/model"
+	"goblog/utils/errmsg"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newReaction 根据路径参数和当前用户构造回应，匿名用户以访客指纹区分
+func newReaction(c *gin.Context, targetType string, reactionType string) (*model.Reaction, *errmsg.AppError) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		if targetType == model.ReactionTargetComment {
+			return nil, errmsg.ErrInvalidCommentID
+		}
+		return nil, errmsg.ErrInvalidArticleID
+	}
+
+	reaction := &model.Reaction{
+		TargetType: targetType,
+		TargetID:   uint(id),
+		Type:       reactionType,
+	}
+	if userID, ok := c.Get("userID"); ok {
+		reaction.UserID = userID.(uint)
+	} else {
+		reaction.Fingerprint = model.VisitorID(c.ClientIP(), c.Request.UserAgent())
+	}
+	return reaction, nil
+}
+
+// addReaction 处理添加回应的请求
+func addReaction(c *gin.Context, targetType string) {
+	var req dto.ReqReaction
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errmsg.BindError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	reaction, appErr := newReaction(c, targetType, req.Type)
+	if appErr != nil {
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	if err := model.AddReaction(reaction); err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.AddReactionSuccess.Status,
+		"message": errmsg.AddReactionSuccess.Message,
+	})
+}
+
+// deleteReaction 处理取消回应的请求
+func deleteReaction(c *gin.Context, targetType string) {
+	reaction, appErr := newReaction(c, targetType, c.Param("type"))
+	if appErr != nil {
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	if err := model.RemoveReaction(reaction); err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.DeleteReactionSuccess.Status,
+		"message": errmsg.DeleteReactionSuccess.Message,
+	})
+}
+
+// AddArticleReaction 对文章点赞或表情回应
+// @Router /api/v1/articles/{id}/reactions [post]
+func AddArticleReaction(c *gin.Context) {
+	addReaction(c, model.ReactionTargetArticle)
+}
+
+// DeleteArticleReaction 取消对文章的回应
+// @Router /api/v1/articles/{id}/reactions/{type} [delete]
+func DeleteArticleReaction(c *gin.Context) {
+	deleteReaction(c, model.ReactionTargetArticle)
+}
+
+// AddCommentReaction 对评论点赞或表情回应
+// @Router /api/v1/comments/{id}/reactions [post]
+func AddCommentReaction(c *gin.Context) {
+	addReaction(c, model.ReactionTargetComment)
+}
+
+// DeleteCommentReaction 取消对评论的回应
+// @Router /api/v1/comments/{id}/reactions/{type} [delete]
+func DeleteCommentReaction(c *gin.Context) {
+	deleteReaction(c, model.ReactionTargetComment)
+}
+
+// GetUserLikes 获取用户点赞过的文章
+// @Router /api/v1/users/{id}/likes [get]
+func GetUserLikes(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		appErr := errmsg.ErrInvalidUserID
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	var req dto.ReqFindLikes
+	if err := c.ShouldBindQuery(&req); err != nil {
+		appErr := errmsg.BindError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	if req.PageNum <= 0 {
+		req.PageNum = 1
+	}
+
+	articles, total, err := model.GetLikedArticles(currentViewer(c), uint(id), req.Sort, req.PageSize, req.PageNum)
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.SUCCESS.Status,
+		"data":    articles,
+		"total":   total,
+		"message": errmsg.SUCCESS.Message,
+	})
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"goblog/dto"
+	"goblog/model"
+	"goblog/utils/errmsg"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSanitizeReports 查询被 HTML 清理器修改过的文章和评论
+// @Router /api/v1/sanitize/reports [get]
+func GetSanitizeReports(c *gin.Context) {
+	var req dto.ReqFindSanitizeReport
+	if err := c.ShouldBindQuery(&req); err != nil {
+		appErr := errmsg.BindError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	if req.PageNum <= 0 {
+		req.PageNum = 1
+	}
+
+	reports, total, err := model.GetSanitizeReports(req.TargetType, req.PageSize, req.PageNum)
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.SUCCESS.Status,
+		"data":    reports,
+		"total":   total,
+		"message": errmsg.SUCCESS.Message,
+	})
+}
+
+// DeleteSanitizeReport 删除 (确认) 清理记录
+// @Router /api/v1/sanitize/reports/{id} [delete]
+func DeleteSanitizeReport(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		appErr := errmsg.ErrInvalidReportID
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	if err := model.DeleteSanitizeReport(uint(id)); err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.DeleteReportSuccess.Status,
+		"message": errmsg.DeleteReportSuccess.Message,
+	})
+}
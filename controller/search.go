@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"goblog/dto"
+	"goblog/model"
+	"goblog/utils"
+	"goblog/utils/errmsg"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchArticles 全文搜索文章
+// @Router /api/v1/search [get]
+func SearchArticles(c *gin.Context) {
+	var req dto.ReqSearch
+	if err := c.ShouldBindQuery(&req); err != nil {
+		appErr := errmsg.BindError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	if req.PageNum <= 0 {
+		req.PageNum = 1
+	}
+
+	opts := &model.SearchOptions{
+		Query:    req.Query,
+		Cid:      req.Cid,
+		AuthorID: req.Author,
+		Viewer:   currentViewer(c),
+		PageSize: req.PageSize,
+		PageNum:  req.PageNum,
+	}
+	// 日期已由 binding 校验格式，结束日期包含当天
+	if req.From != "" {
+		opts.From, _ = time.ParseInLocation(utils.TimeFormatDateV1, req.From, time.Local)
+	}
+	if req.To != "" {
+		to, _ := time.ParseInLocation(utils.TimeFormatDateV1, req.To, time.Local)
+		opts.To = to.AddDate(0, 0, 1)
+	}
+
+	hits, total, err := model.SearchArticles(opts)
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.SUCCESS.Status,
+		"data":    hits,
+		"total":   total,
+		"message": errmsg.SUCCESS.Message,
+	})
+}
@@ -0,0 +1,228 @@
+package controller
+
+import (
+	"goblog/dto"
+	"goblog/model"
+	"goblog/utils/errmsg"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSeriesList 查询系列列表
+// @Router /api/v1/series [get]
+func GetSeriesList(c *gin.Context) {
+	var req dto.ReqFindSeries
+	if err := c.ShouldBindQuery(&req); err != nil {
+		appErr := errmsg.BindError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	if req.PageNum <= 0 {
+		req.PageNum = 1
+	}
+
+	list, total, err := model.GetSeriesList(req.PageSize, req.PageNum)
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.SUCCESS.Status,
+		"data":    list,
+		"total":   total,
+		"message": errmsg.SUCCESS.Message,
+	})
+}
+
+// GetSeries 获取系列详情及其文章目录
+// @Router /api/v1/series/{id} [get]
+func GetSeries(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		appErr := errmsg.ErrInvalidSeriesID
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	series, err := model.GetSeries(uint(id), currentViewer(c))
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.SUCCESS.Status,
+		"data":    series,
+		"message": errmsg.SUCCESS.Message,
+	})
+}
+
+// AddSeries 创建系列
+// @Router /api/v1/series [post]
+func AddSeries(c *gin.Context) {
+	var req dto.ReqSeries
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errmsg.BindError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	series := &model.Series{Title: req.Title, Desc: req.Desc}
+	if err := model.CreateSeries(series); err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.CreateSeriesSuccess.Status,
+		"data":    series,
+		"message": errmsg.CreateSeriesSuccess.Message,
+	})
+}
+
+// EditSeries 编辑系列
+// @Router /api/v1/series/{id} [put]
+func EditSeries(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		appErr := errmsg.ErrInvalidSeriesID
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	var req dto.ReqSeries
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errmsg.BindError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	if err := model.EditSeries(uint(id), &model.Series{Title: req.Title, Desc: req.Desc}); err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.UpdateSeriesSuccess.Status,
+		"message": errmsg.UpdateSeriesSuccess.Message,
+	})
+}
+
+// DeleteSeries 删除系列
+// @Router /api/v1/series/{id} [delete]
+func DeleteSeries(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		appErr := errmsg.ErrInvalidSeriesID
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	if err := model.DeleteSeries(uint(id)); err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.DeleteSeriesSuccess.Status,
+		"message": errmsg.DeleteSeriesSuccess.Message,
+	})
+}
+
+// AddSeriesArticle 将文章追加到系列末尾
+// @Router /api/v1/series/{id}/articles [post]
+func AddSeriesArticle(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		appErr := errmsg.ErrInvalidSeriesID
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	var req dto.ReqSeriesArticle
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errmsg.BindError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	if err := model.AddSeriesArticle(uint(id), req.ArticleID); err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.UpdateSeriesSuccess.Status,
+		"message": errmsg.UpdateSeriesSuccess.Message,
+	})
+}
+
+// SetSeriesArticles 按给定顺序设置系列中的文章 (用于调整顺序)
+// @Router /api/v1/series/{id}/articles [put]
+func SetSeriesArticles(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		appErr := errmsg.ErrInvalidSeriesID
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	var req dto.ReqSeriesArticles
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errmsg.BindError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	if err := model.SetSeriesArticles(uint(id), req.ArticleIDs); err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.UpdateSeriesSuccess.Status,
+		"message": errmsg.UpdateSeriesSuccess.Message,
+	})
+}
+
+// RemoveSeriesArticle 将文章移出系列
+// @Router /api/v1/series/{id}/articles/{articleId} [delete]
+func RemoveSeriesArticle(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		appErr := errmsg.ErrInvalidSeriesID
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+	articleID, err := strconv.ParseUint(c.Param("articleId"), 10, 0)
+	if err != nil {
+		appErr := errmsg.ErrInvalidArticleID
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	if err := model.RemoveSeriesArticle(uint(id), uint(articleID)); err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.UpdateSeriesSuccess.Status,
+		"message": errmsg.UpdateSeriesSuccess.Message,
+	})
+}
@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"goblog/model"
+	"goblog/utils"
+	"goblog/utils/errmsg"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSitemapIndex 获取站点地图索引
+// @Router /sitemap.xml [get]
+func GetSitemapIndex(c *gin.Context) {
+	serveSitemap(c, model.SitemapIndexName)
+}
+
+// GetSitemap 获取文章、分类或标签的站点地图
+// @Router /sitemaps/{name} [get]
+func GetSitemap(c *gin.Context) {
+	serveSitemap(c, c.Param("name"))
+}
+
+func serveSitemap(c *gin.Context, name string) {
+	doc, err := model.GetSitemap(name)
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	serveDocument(c, doc)
+}
+
+// GetRobots 获取 robots.txt
+// @Router /robots.txt [get]
+func GetRobots(c *gin.Context) {
+	if utils.RobotsFile != "" {
+		c.File(utils.RobotsFile)
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString("User-agent: *\n")
+	if len(utils.RobotsDisallow) == 0 {
+		b.WriteString("Disallow:\n")
+	}
+	for _, path := range utils.RobotsDisallow {
+		b.WriteString("Disallow: " + path + "\n")
+	}
+	b.WriteString("\nSitemap: " + utils.SiteURL + "/" + model.SitemapIndexName + "\n")
+	c.String(http.StatusOK, b.String())
+}
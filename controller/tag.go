@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"goblog/model"
+	"goblog/utils/errmsg"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTags 获取所有标签及其文章数量
+// @Router /api/v1/tags [get]
+func GetTags(c *gin.Context) {
+	tags, err := model.GetTags()
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.SUCCESS.Status,
+		"data":    tags,
+		"message": errmsg.SUCCESS.Message,
+	})
+}
+
+// newTags 将请求中的标签名转换为标签模型
+func newTags(names []string) []model.Tag {
+	tags := make([]model.Tag, 0, len(names))
+	for _, name := range names {
+		tags = append(tags, model.Tag{Name: name})
+	}
+	return tags
+}
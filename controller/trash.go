@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"goblog/dto"
+	"goblog/model"
+	"goblog/utils/errmsg"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTrash 查询回收站中已删除的文章、评论或用户
+// @Router /api/v1/trash/{kind} [get]
+func GetTrash(c *gin.Context) {
+	var req dto.ReqFindTrash
+	if err := c.ShouldBindQuery(&req); err != nil {
+		appErr := errmsg.BindError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	if req.PageSize <= 0 {
+		req.PageSize = 10
+	}
+	if req.PageNum <= 0 {
+		req.PageNum = 1
+	}
+
+	items, total, err := model.GetTrash(c.Param("kind"), req.PageSize, req.PageNum)
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.SUCCESS.Status,
+		"data":    items,
+		"total":   total,
+		"message": errmsg.SUCCESS.Message,
+	})
+}
+
+// RestoreTrash 从回收站恢复内容，恢复文章时一并恢复随文章删除的评论
+// @Router /api/v1/trash/{kind}/{id}/restore [post]
+func RestoreTrash(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		appErr := errmsg.ErrInvalidTrashID
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	if err := model.RestoreTrash(c.Param("kind"), uint(id)); err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.RestoreTrashSuccess.Status,
+		"message": errmsg.RestoreTrashSuccess.Message,
+	})
+}
+
+// PurgeTrash 彻底删除回收站中的内容
+// @Router /api/v1/trash/{kind}/{id} [delete]
+func PurgeTrash(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		appErr := errmsg.ErrInvalidTrashID
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	if err := model.PurgeTrash(c.Param("kind"), uint(id)); err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.PurgeTrashSuccess.Status,
+		"message": errmsg.PurgeTrashSuccess.Message,
+	})
+}
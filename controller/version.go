@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"errors"
+	"goblog/model"
+	"goblog/utils/errmsg"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setETag 以版本号作为 ETag 响应头
+func setETag(c *gin.Context, version uint) {
+	c.Header("ETag", `"`+strconv.FormatUint(uint64(version), 10)+`"`)
+}
+
+// editVersion 返回编辑所基于的版本，优先使用 If-Match 请求头，其次使用请求体中的 version。
+// ifMatch 表示版本来自 If-Match；If-Match 为 "*" 时返回 0，表示不校验版本
+func editVersion(c *gin.Context, bodyVersion uint) (version uint, ifMatch bool, appErr *errmsg.AppError) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		if bodyVersion == 0 {
+			return 0, false, errmsg.ErrVersionRequired
+		}
+		return bodyVersion, false, nil
+	}
+	if header == "*" {
+		return 0, true, nil
+	}
+
+	tag := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	v, err := strconv.ParseUint(tag, 10, 0)
+	if err != nil || v == 0 {
+		return 0, true, errmsg.ErrInvalidParams.WithMsg("If-Match 请求头格式无效: %s", header)
+	}
+	return uint(v), true, nil
+}
+
+// respondEditError 输出编辑失败的响应。版本冲突时附带服务器上的当前版本，
+// 版本来自 If-Match 时返回 412，来自请求体时返回 409
+func respondEditError(c *gin.Context, err error, ifMatch bool) {
+	var conflict *model.VersionConflict
+	if !errors.As(err, &conflict) {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	appErr := errmsg.ErrVersionConflict
+	if ifMatch {
+		appErr = errmsg.ErrVersionMismatch
+	}
+	setETag(c, conflict.Current)
+	c.JSON(appErr.HTTPStatus, gin.H{
+		"status":  appErr.Status,
+		"version": conflict.Current,
+		"message": appErr.Message,
+	})
+}
+
+// respondEdited 输出编辑成功的响应，附带新的版本号
+func respondEdited(c *gin.Context, success *errmsg.AppError, version uint) {
+	setETag(c, version)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  success.Status,
+		"version": version,
+		"message": success.Message,
+	})
+}
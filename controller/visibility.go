@@ -0,0 +1,61 @@
+package controller
+
+import (
+	"fmt"
+	"goblog/dto"
+	"goblog/model"
+	"goblog/utils/errmsg"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// currentViewer 返回当前访问者，未登录时为匿名访问者
+func currentViewer(c *gin.Context) model.Viewer {
+	var v model.Viewer
+	if userID, ok := c.Get("userID"); ok {
+		v.UserID = userID.(uint)
+	}
+	if role, ok := c.Get("role"); ok {
+		v.Role = role.(int)
+	}
+	return v
+}
+
+// unlockCookieName 返回文章解锁凭证的 cookie 名称
+func unlockCookieName(id uint) string {
+	return fmt.Sprintf("article_unlock_%d", id)
+}
+
+// UnlockArticle 输入密码解锁受保护的文章，成功后下发短期有效的签名 cookie
+// @Router /api/v1/articles/{id}/unlock [post]
+func UnlockArticle(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil {
+		appErr := errmsg.ErrInvalidArticleID
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	var req dto.ReqUnlockArticle
+	if err := c.ShouldBindJSON(&req); err != nil {
+		appErr := errmsg.BindError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	token, err := model.UnlockArticle(uint(id), req.Password)
+	if err != nil {
+		appErr := errmsg.FromError(err)
+		c.JSON(appErr.HTTPStatus, appErr)
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(unlockCookieName(uint(id)), token, int(model.UnlockTTL.Seconds()), "/", "", c.Request.TLS != nil, true)
+	c.JSON(http.StatusOK, gin.H{
+		"status":  errmsg.UnlockArticleSuccess.Status,
+		"message": errmsg.UnlockArticleSuccess.Message,
+	})
+}
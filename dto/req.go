@@ -11,9 +11,10 @@ type ReqLogin struct {
 }
 
 type ReqRegister struct {
-	Username string `json:"username" binding:"required,min=4,max=20"`
-	Password string `json:"password" binding:"required,min=6,max=20"`
-	Email    string `json:"email"    binding:"required,email"`
+	Username   string `json:"username"   binding:"required,min=4,max=20"`
+	Password   string `json:"password"   binding:"required,min=6,max=20"`
+	Email      string `json:"email"      binding:"required,email"`
+	InviteCode string `json:"inviteCode" binding:"omitempty,max=40"`
 }
 
 type ReqLoginByEmail struct {
@@ -47,6 +48,24 @@ type ReqEditUser struct {
 	Role     int    `json:"role"     binding:"required,oneof=1 2"`
 }
 
+type ReqFindInvite struct {
+	PageReq
+}
+
+type ReqFindTrash struct {
+	PageReq
+}
+
+type ReqFindSanitizeReport struct {
+	PageReq
+	TargetType string `form:"type" binding:"omitempty,oneof=article comment"`
+}
+
+type ReqInvite struct {
+	MaxUses     int `json:"maxUses"     binding:"omitempty,gte=1,lte=1000"`
+	ExpireHours int `json:"expireHours" binding:"omitempty,gte=1"` // 为空表示永不过期
+}
+
 type ReqUpdateProfile struct {
 	Name   string `json:"name"   binding:"omitempty,max=50"`
 	Desc   string `json:"desc"   binding:"omitempty,max=200"`
@@ -54,6 +73,12 @@ type ReqUpdateProfile struct {
 	Weibo  string `json:"weibo"  binding:"omitempty,max=50"`
 	Img    string `json:"img"    binding:"omitempty,url,max=255"`
 	Avatar string `json:"avatar" binding:"omitempty,url,max=255"`
+
+	Version uint `json:"version"` // 编辑所基于的版本，未携带 If-Match 请求头时必填
+}
+
+type ReqAppPassword struct {
+	Name string `json:"name" binding:"required,max=50"` // 用途，如使用的客户端名称
 }
 
 type ReqFindCate struct {
@@ -62,22 +87,128 @@ type ReqFindCate struct {
 
 type ReqCategory struct {
 	Name string `json:"name" binding:"required,min=2,max=20"`
+	Slug string `json:"slug" binding:"omitempty,max=100"` // 为空时根据分类名自动生成
+
+	Version uint `json:"version"` // 编辑所基于的版本，未携带 If-Match 请求头时必填
 }
 
 type ReqFindArticle struct {
 	PageReq
 	Title string `form:"title" json:"title"`
+	Sort  string `form:"sort"  json:"sort"  binding:"omitempty,oneof=newest oldest views comments updated"`
 }
 
 type ReqArticle struct {
-	Title   string `json:"title"   binding:"required,min=2,max=100"`
-	Cid     uint   `json:"cid"     binding:"required,gte=1"`
-	Desc    string `json:"desc"    binding:"required,max=200"`
-	Content string `json:"content" binding:"required"`
-	Img     string `json:"img"     binding:"omitempty,url"`
+	Title   string   `json:"title"   binding:"required,min=2,max=100"`
+	Slug    string   `json:"slug"    binding:"omitempty,max=100"` // 为空时新文章根据标题自动生成，编辑时保留原 slug
+	Cid     uint     `json:"cid"     binding:"required,gte=1"`
+	Desc    string   `json:"desc"    binding:"required,max=200"`
+	Content string   `json:"content" binding:"required"`
+	Img     string   `json:"img"     binding:"omitempty,url"`
+	Tags    []string `json:"tags"    binding:"omitempty,max=10,dive,min=1,max=20"`
+
+	Pinned     bool `json:"pinned"`
+	Featured   bool `json:"featured"`
+	SortWeight int  `json:"sortWeight" binding:"omitempty,gte=-1000,lte=1000"`
+
+	Visibility  string `json:"visibility"  binding:"omitempty,oneof=public unlisted private role password"` // 为空表示公开
+	VisibleRole int    `json:"visibleRole" binding:"required_if=Visibility role,omitempty,oneof=1 2"`
+	Password    string `json:"password"    binding:"omitempty,min=4,max=50"` // 可见性为 password 时的访问密码，编辑时为空表示不修改
+	Draft       bool   `json:"draft"`                                        // 保存为草稿，不发布
+
+	Version uint `json:"version"` // 编辑所基于的版本，未携带 If-Match 请求头时必填
+}
+
+type ReqBulkArticles struct {
+	Op     string   `json:"op"     binding:"required,oneof=category add_tags remove_tags publish unpublish trash restore"`
+	IDs    []uint   `json:"ids"    binding:"required,min=1,max=500,dive,gte=1"`
+	Cid    uint     `json:"cid"    binding:"required_if=Op category"`
+	Tags   []string `json:"tags"   binding:"required_if=Op add_tags,required_if=Op remove_tags,max=10,dive,min=1,max=20"`
+	Atomic bool     `json:"atomic"` // 为 true 时任意一篇失败则全部回滚
+}
+
+type ReqFindAuditLog struct {
+	PageReq
+	Action string `form:"action" binding:"omitempty,max=50"` // 以 "." 结尾时按前缀匹配，如 article.bulk.
+	UserID uint   `form:"userId"`
+}
+
+type ReqImport struct {
+	DryRun   *bool  `form:"dryRun"`                              // 默认为 true，只生成报告；确认无误后传 false 执行导入
+	Category string `form:"category" binding:"omitempty,max=20"` // 没有分类的文章归入该分类
+}
+
+type ReqExport struct {
+	Media bool `form:"media"` // 同时打包上传到对象存储的图片，文章中的链接改为相对路径
+}
+
+type ReqAutosave struct {
+	BaseVersion uint     `json:"baseVersion"` // 开始编辑时文章的版本号，新文章为 0
+	Title       string   `json:"title"   binding:"max=100"`
+	Cid         uint     `json:"cid"`
+	Desc        string   `json:"desc"    binding:"max=200"`
+	Content     string   `json:"content"`
+	Img         string   `json:"img"     binding:"max=200"`
+	Tags        []string `json:"tags"    binding:"omitempty,max=10,dive,max=20"`
+}
+
+type ReqUnlockArticle struct {
+	Password string `json:"password" binding:"required,max=50"`
+}
+
+type ReqFeatured struct {
+	Limit int `form:"limit" binding:"omitempty,gte=1,lte=20"`
+}
+
+type ReqFindArchive struct {
+	PageReq
+}
+
+type ReqFindSeries struct {
+	PageReq
+}
+
+type ReqSeries struct {
+	Title string `json:"title" binding:"required,min=2,max=100"`
+	Desc  string `json:"desc"  binding:"omitempty,max=200"`
+}
+
+type ReqSeriesArticle struct {
+	ArticleID uint `json:"articleId" binding:"required,gte=1"`
+}
+
+type ReqSeriesArticles struct {
+	ArticleIDs []uint `json:"articleIds" binding:"omitempty,max=200,dive,gte=1"` // 按顺序排列的文章ID，为空表示清空系列
+}
+
+type ReqPopular struct {
+	Period string `form:"period" binding:"omitempty,oneof=day week month"`
+	Limit  int    `form:"limit"  binding:"omitempty,gte=1,lte=50"`
+}
+
+type ReqRelated struct {
+	Limit int `form:"limit" binding:"omitempty,gte=1,lte=20"`
+}
+
+type ReqSearch struct {
+	PageReq
+	Query  string `form:"q"      binding:"required,max=100"`
+	Cid    uint   `form:"cid"    binding:"omitempty,gte=1"`
+	Author uint   `form:"author" binding:"omitempty,gte=1"`
+	From   string `form:"from"   binding:"omitempty,datetime=2006-01-02"` // 发布日期起 (含)
+	To     string `form:"to"     binding:"omitempty,datetime=2006-01-02"` // 发布日期止 (含)
 }
 
 type ReqAddComment struct {
 	ArticleID uint   `json:"articleId" binding:"required,gte=1"`
 	Content   string `json:"content"    binding:"required,max=500"`
 }
+
+type ReqReaction struct {
+	Type string `json:"type" binding:"required,max=20"`
+}
+
+type ReqFindLikes struct {
+	PageReq
+	Sort string `form:"sort" binding:"omitempty,oneof=newest oldest views comments updated"` // 为空时按点赞时间倒序
+}
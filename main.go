@@ -1,12 +1,24 @@
 package main
 
 import (
+	"goblog/cmd"
+	"goblog/middleware"
 	"goblog/model"
 	"goblog/router"
+	"os"
 )
 
 func main() {
+	if cmd.Execute(os.Args[1:]) {
+		return
+	}
+
+	middleware.InitJwtKeys()
 	model.InitDb()
+	model.InitSearch()
 	model.InitRedis()
+	model.InitViewCounter()
+	model.InitTrashCleaner()
+	model.InitAutosave()
 	router.InitRouter()
 }
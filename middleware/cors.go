@@ -11,9 +11,9 @@ func Cors() gin.HandlerFunc {
 		method := c.Request.Method
 		origin := c.Request.Header.Get("Origin")
 		c.Header("Access-Control-Allow-Origin", origin)
-		c.Header("Access-Control-Allow-Headers", "Content-Type,AccessToken,X-CSRF-Token,Content-Length,Authorization,Token,X-User-Id,x-requested-with")
+		c.Header("Access-Control-Allow-Headers", "Content-Type,AccessToken,X-CSRF-Token,Content-Length,Authorization,Token,X-User-Id,x-requested-with,If-Match")
 		c.Header("Access-Control-Allow-Methods", "POST,GET,OPTIONS,DELETE,PUT")
-		c.Header("Access-Control-Expose-Headers", "Content-Length,Access-Control-Allow-Origin,Access-Control-Allow-Headers,Content-Type")
+		c.Header("Access-Control-Expose-Headers", "Content-Length,Access-Control-Allow-Origin,Access-Control-Allow-Headers,Content-Type,ETag")
 		c.Header("Access-Control-Allow-Createntials", "true")
 
 		if method == "OPTIONS" {
@@ -0,0 +1,289 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"goblog/utils"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// legacyKid 是由 config.ini 中 JwtKey 派生出的 HS256 密钥的 kid，
+// 用于兼容不带 kid 头的旧 token
+const legacyKid = "default"
+
+// SigningKey 表示一把可用于签发或校验 JWT 的密钥
+type SigningKey struct {
+	Kid       string    `json:"kid"`
+	Alg       string    `json:"alg"`
+	Key       string    `json:"key"` // HS256 为 base64 编码的密钥，RS256/EdDSA 为 PKCS#8 PEM 私钥
+	CreatedAt time.Time `json:"createdAt"`
+
+	signKey   any
+	verifyKey any
+}
+
+// KeySet 是密钥文件的结构，Active 指向当前用于签发的密钥
+type KeySet struct {
+	Active string        `json:"active"`
+	Keys   []*SigningKey `json:"keys"`
+}
+
+// JWK 是 JWKS 中单个公钥的表示 (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+var (
+	keyMu     sync.RWMutex
+	activeKey *SigningKey
+	keysByKid = map[string]*SigningKey{}
+)
+
+// InitJwtKeys 加载密钥文件并设置当前签发密钥。
+// 密钥文件不存在时回退到 config.ini 中的 JwtKey (HS256)，
+// 在 release 模式下若仍使用默认密钥则拒绝启动。
+func InitJwtKeys() {
+	set, err := LoadKeySet(utils.JwtKeyFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Fatalf("加载 JWT 密钥文件失败: %v", err)
+	}
+
+	if set == nil || len(set.Keys) == 0 {
+		if utils.AppMode == "release" && utils.JwtKey == utils.DefaultJwtKey {
+			log.Fatalf("release 模式下禁止使用默认 JwtKey，请在 config.ini 中配置 JwtKey 或生成密钥文件 %s", utils.JwtKeyFile)
+		}
+		set = &KeySet{}
+	}
+
+	if err := applyKeySet(set); err != nil {
+		log.Fatalf("初始化 JWT 密钥失败: %v", err)
+	}
+}
+
+// applyKeySet 解析密钥集合并替换当前内存中的密钥
+func applyKeySet(set *KeySet) error {
+	byKid := make(map[string]*SigningKey, len(set.Keys)+1)
+	for _, k := range set.Keys {
+		if err := k.parse(); err != nil {
+			return fmt.Errorf("密钥 %s 无效: %w", k.Kid, err)
+		}
+		byKid[k.Kid] = k
+	}
+
+	// 始终保留由 JwtKey 派生的旧密钥，使轮换前签发的 token 在过期前仍然有效
+	if _, ok := byKid[legacyKid]; !ok && (len(set.Keys) == 0 || utils.JwtKey != utils.DefaultJwtKey) {
+		legacy := &SigningKey{Kid: legacyKid, Alg: jwt.SigningMethodHS256.Alg(), signKey: JwtKey, verifyKey: JwtKey}
+		byKid[legacyKid] = legacy
+	}
+
+	active, ok := byKid[set.Active]
+	if !ok {
+		if len(set.Keys) > 0 {
+			return fmt.Errorf("当前签发密钥 %q 不存在", set.Active)
+		}
+		active = byKid[legacyKid]
+	}
+
+	keyMu.Lock()
+	defer keyMu.Unlock()
+	keysByKid = byKid
+	activeKey = active
+	return nil
+}
+
+// currentKey 返回当前用于签发 token 的密钥
+func currentKey() *SigningKey {
+	keyMu.RLock()
+	defer keyMu.RUnlock()
+	return activeKey
+}
+
+// lookupKey 根据 kid 查找密钥，没有 kid 的旧 token 使用 legacyKid
+func lookupKey(kid string) (*SigningKey, bool) {
+	if kid == "" {
+		kid = legacyKid
+	}
+	keyMu.RLock()
+	defer keyMu.RUnlock()
+	k, ok := keysByKid[kid]
+	return k, ok
+}
+
+// method 返回密钥对应的签名算法
+func (k *SigningKey) method() jwt.SigningMethod {
+	return jwt.GetSigningMethod(k.Alg)
+}
+
+// parse 将文件中的密钥文本解析为签名/校验所需的密钥对象
+func (k *SigningKey) parse() error {
+	switch k.Alg {
+	case jwt.SigningMethodHS256.Alg():
+		secret, err := base64.StdEncoding.DecodeString(k.Key)
+		if err != nil {
+			return err
+		}
+		k.signKey, k.verifyKey = secret, secret
+	case jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg():
+		block, _ := pem.Decode([]byte(k.Key))
+		if block == nil {
+			return errors.New("无法解析 PEM 数据")
+		}
+		priv, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return err
+		}
+		signer, ok := priv.(crypto.Signer)
+		if !ok {
+			return errors.New("不支持的私钥类型")
+		}
+		switch signer.(type) {
+		case *rsa.PrivateKey:
+			if k.Alg != jwt.SigningMethodRS256.Alg() {
+				return errors.New("RSA 私钥只能用于 RS256")
+			}
+		case ed25519.PrivateKey:
+			if k.Alg != jwt.SigningMethodEdDSA.Alg() {
+				return errors.New("Ed25519 私钥只能用于 EdDSA")
+			}
+		default:
+			return errors.New("不支持的私钥类型")
+		}
+		k.signKey, k.verifyKey = signer, signer.Public()
+	default:
+		return fmt.Errorf("不支持的算法 %s", k.Alg)
+	}
+	return nil
+}
+
+// jwk 返回密钥的公钥 JWK 表示，对称密钥不公开
+func (k *SigningKey) jwk() (JWK, bool) {
+	enc := base64.RawURLEncoding
+	switch pub := k.verifyKey.(type) {
+	case *rsa.PublicKey:
+		return JWK{
+			Kty: "RSA", Kid: k.Kid, Alg: k.Alg, Use: "sig",
+			N: enc.EncodeToString(pub.N.Bytes()),
+			E: enc.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}, true
+	case ed25519.PublicKey:
+		return JWK{Kty: "OKP", Kid: k.Kid, Alg: k.Alg, Use: "sig", Crv: "Ed25519", X: enc.EncodeToString(pub)}, true
+	}
+	return JWK{}, false
+}
+
+// GenerateSigningKey 生成一把指定算法的新密钥
+func GenerateSigningKey(alg string) (*SigningKey, error) {
+	k := &SigningKey{Alg: alg, CreatedAt: time.Now()}
+	k.Kid = fmt.Sprintf("%s-%s", k.CreatedAt.Format("20060102150405"), utils.CreateVcode())
+
+	switch alg {
+	case jwt.SigningMethodHS256.Alg():
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+		k.Key = base64.StdEncoding.EncodeToString(secret)
+	case jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg():
+		var priv any
+		var err error
+		if alg == jwt.SigningMethodRS256.Alg() {
+			priv, err = rsa.GenerateKey(rand.Reader, 2048)
+		} else {
+			_, priv, err = ed25519.GenerateKey(rand.Reader)
+		}
+		if err != nil {
+			return nil, err
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(priv)
+		if err != nil {
+			return nil, err
+		}
+		k.Key = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	default:
+		return nil, fmt.Errorf("不支持的算法 %s，可选 HS256、RS256、EdDSA", alg)
+	}
+
+	return k, k.parse()
+}
+
+// LoadKeySet 从 JSON 文件读取密钥集合
+func LoadKeySet(path string) (*KeySet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var set KeySet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+	return &set, nil
+}
+
+// SaveKeySet 将密钥集合写入 JSON 文件，文件仅对当前用户可读写
+func SaveKeySet(path string, set *KeySet) error {
+	data, err := json.MarshalIndent(set, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// RotateKeySet 生成新密钥并设为当前签发密钥，旧密钥保留用于校验，
+// 超过 keep 把的最旧密钥会被移除 (keep <= 0 表示全部保留)
+func RotateKeySet(set *KeySet, alg string, keep int) (*SigningKey, error) {
+	k, err := GenerateSigningKey(alg)
+	if err != nil {
+		return nil, err
+	}
+	set.Keys = append(set.Keys, k)
+	set.Active = k.Kid
+	if keep > 0 && len(set.Keys) > keep {
+		set.Keys = set.Keys[len(set.Keys)-keep:]
+	}
+	return k, nil
+}
+
+// JWKS 返回当前所有非对称密钥的公钥集合
+// @Router /api/v1/.well-known/jwks.json [get]
+func JWKS(c *gin.Context) {
+	keyMu.RLock()
+	keys := make([]JWK, 0, len(keysByKid))
+	for _, k := range keysByKid {
+		if jwk, ok := k.jwk(); ok {
+			keys = append(keys, jwk)
+		}
+	}
+	keyMu.RUnlock()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Kid < keys[j].Kid })
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, gin.H{"keys": keys})
+}
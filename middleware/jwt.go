@@ -5,6 +5,8 @@
 	"goblog/model"
 	"goblog/utils"
 	"goblog/utils/errmsg"
+	"mime"
+	"net/http"
 	"strings"
 	"time"
 
@@ -30,8 +32,10 @@ func SetToken(username string) (string, *errmsg.AppError) {
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(JwtKey)
+	key := currentKey()
+	token := jwt.NewWithClaims(key.method(), claims)
+	token.Header["kid"] = key.Kid
+	tokenString, err := token.SignedString(key.signKey)
 	if err != nil {
 		return "", errmsg.ErrInternalServer.WithMsg("Failed to sign token: %v", err)
 	}
@@ -42,7 +46,16 @@ func SetToken(username string) (string, *errmsg.AppError) {
 // CheckToken validates the JWT Token.
 func CheckToken(tokenString string) (*MyClaims, *errmsg.AppError) {
 	token, err := jwt.ParseWithClaims(tokenString, &MyClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return JwtKey, nil
+		kid, _ := token.Header["kid"].(string)
+		key, ok := lookupKey(kid)
+		if !ok {
+			return nil, errors.New("unknown kid")
+		}
+		// 算法必须与密钥匹配，防止用公钥作为 HMAC 密钥的算法混淆攻击
+		if token.Method.Alg() != key.Alg {
+			return nil, errors.New("unexpected signing method")
+		}
+		return key.verifyKey, nil
 	})
 
 	if err != nil {
@@ -64,43 +77,110 @@ func CheckToken(tokenString string) (*MyClaims, *errmsg.AppError) {
 	return nil, errmsg.ErrTokenWrong
 }
 
+// authenticate 从 Authorization 头中解析 token 并查找对应的用户
+func authenticate(c *gin.Context) (*model.User, *errmsg.AppError) {
+	tokenHeader := c.Request.Header.Get("Authorization")
+	if tokenHeader == "" {
+		return nil, errmsg.ErrTokenNotExist
+	}
+
+	checkToken := strings.SplitN(tokenHeader, " ", 2)
+	if len(checkToken) != 2 || checkToken[0] != "Bearer" {
+		return nil, errmsg.ErrTokenTypeWrong
+	}
+
+	claims, appErr := CheckToken(checkToken[1])
+	if appErr != nil {
+		return nil, appErr
+	}
+
+	user, err := model.FindUserByName(claims.Username)
+	if err != nil {
+		return nil, errmsg.ErrUserNotExist
+	}
+	return user, nil
+}
+
+// setUser 将当前用户信息写入上下文
+func setUser(c *gin.Context, user *model.User) {
+	c.Set("username", user.Username)
+	c.Set("userID", user.ID)
+	c.Set("role", user.Role)
+}
+
 // JwtToken is a Gin middleware for protecting authenticated routes.
 func JwtToken() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		tokenHeader := c.Request.Header.Get("Authorization")
-		if tokenHeader == "" {
-			appErr := errmsg.ErrTokenNotExist
+		user, appErr := authenticate(c)
+		if appErr != nil {
 			c.JSON(appErr.HTTPStatus, appErr)
 			c.Abort()
 			return
 		}
 
-		checkToken := strings.SplitN(tokenHeader, " ", 2)
-		if len(checkToken) != 2 || checkToken[0] != "Bearer" {
-			appErr := errmsg.ErrTokenTypeWrong
-			c.JSON(appErr.HTTPStatus, appErr)
-			c.Abort()
+		setUser(c, user)
+		c.Next()
+	}
+}
+
+// OptionalJwtToken 在携带有效 token 时写入当前用户信息，未携带时按匿名访问放行
+func OptionalJwtToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Header.Get("Authorization") == "" {
+			c.Next()
 			return
 		}
 
-		claims, appErr := CheckToken(checkToken[1])
+		user, appErr := authenticate(c)
 		if appErr != nil {
 			c.JSON(appErr.HTTPStatus, appErr)
 			c.Abort()
 			return
 		}
 
-		user, err := model.FindUserByName(claims.Username)
-		if err != nil {
-			appErr := errmsg.ErrUserNotExist
-			c.JSON(appErr.HTTPStatus, appErr)
-			c.Abort()
+		setUser(c, user)
+		c.Next()
+	}
+}
+
+// AccessTokenParam 允许在 access_token 查询参数或表单字段中携带 token (Micropub 规范要求)，需在 JwtToken 之前使用。
+// 请求头中已有 Authorization 时不做处理；表单在这里解析，maxBody 和 maxMemory 应与处理函数的限制一致
+func AccessTokenParam(maxBody, maxMemory int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Header.Get("Authorization") != "" {
+			c.Next()
 			return
 		}
 
-		c.Set("username", user.Username)
-		c.Set("userID", user.ID)
+		token := c.Query("access_token")
+		if token == "" {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBody)
+			// 解析失败时由处理函数返回表单错误，这里只按未携带 token 处理
+			mediaType, _, _ := mime.ParseMediaType(c.Request.Header.Get("Content-Type"))
+			switch mediaType {
+			case "multipart/form-data":
+				_ = c.Request.ParseMultipartForm(maxMemory)
+			case "application/x-www-form-urlencoded":
+				_ = c.Request.ParseForm()
+			}
+			token = c.Request.PostForm.Get("access_token")
+		}
+		if token != "" {
+			c.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+		c.Next()
+	}
+}
 
+// AdminAuth 要求当前用户为管理员，需在 JwtToken 之后使用
+func AdminAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if role, _ := c.Get("role"); role != 1 {
+			appErr := errmsg.ErrNoAdminPermission
+			c.JSON(appErr.HTTPStatus, appErr)
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
@@ -3,34 +3,99 @@
 import (
 	"errors"
 	"goblog/utils/errmsg"
+	"goblog/utils/markdown"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 type Article struct {
 	BaseModel
-	Title    string   `gorm:"type:varchar(100);not null" json:"title"`
-	Cid      uint     `gorm:"notnull" json:"cid"`
-	Desc     string   `gorm:"type:varchar(200)" json:"desc"`
-	Content  string   `gorm:"type:longtext;not null" json:"content"`
-	Img      string   `gorm:"type:varchar(200)" json:"img"`
-	Category Category `gorm:"foreignkey:Cid" json:"category"`
-	Comments []Comment
+	Title       string             `gorm:"type:varchar(100);not null" json:"title"`
+	Slug        string             `gorm:"type:varchar(100);not null;default:''" json:"slug"`
+	Cid         uint               `gorm:"notnull" json:"cid"`
+	Desc        string             `gorm:"type:varchar(200)" json:"desc"`
+	Content     string             `gorm:"type:longtext;not null" json:"content"`
+	Img         string             `gorm:"type:varchar(200)" json:"img"`
+	ViewCount   int64              `gorm:"not null;default:0" json:"viewCount"`
+	Version     uint               `gorm:"not null;default:1" json:"version"`            // 乐观锁版本号，每次编辑加一
+	Pinned      bool               `gorm:"not null;default:false;index" json:"pinned"`   // 置顶
+	Featured    bool               `gorm:"not null;default:false;index" json:"featured"` // 精选 (用于首页轮播)
+	SortWeight  int                `gorm:"not null;default:0" json:"sortWeight"`         // 手动排序权重，越大越靠前
+	Visibility  string             `gorm:"type:varchar(10);not null;default:'public';index" json:"visibility"`
+	VisibleRole int                `gorm:"not null;default:0" json:"visibleRole,omitempty"` // 可见性为 role 时允许查看的角色
+	Password    string             `gorm:"type:varchar(128);not null;default:''" json:"-"`  // 可见性为 password 时的访问密码 (哈希)
+	Draft       bool               `gorm:"not null;default:false;index" json:"draft"`       // 草稿 (未发布)，仅作者和管理员可见
+	Locked      bool               `gorm:"-" json:"locked,omitempty"`                       // 受密码保护，正文已隐藏
+	Reactions   map[string]int64   `gorm:"-" json:"reactions"`
+	HTML        string             `gorm:"-" json:"html,omitempty"` // 渲染后的正文
+	Toc         []markdown.TocItem `gorm:"-" json:"toc,omitempty"`
+	WordCount   int                `gorm:"-" json:"wordCount,omitempty"`
+	ReadingTime int                `gorm:"-" json:"readingTime,omitempty"` // 预计阅读时长 (分钟)
+	Series      *SeriesNav         `gorm:"-" json:"series,omitempty"`      // 所属系列的导航
+	Category    Category           `gorm:"foreignkey:Cid" json:"category"`
+	Tags        []Tag              `gorm:"many2many:article_tag" json:"tags"`
+	Comments    []Comment
 }
 
 type Comment struct {
 	BaseModel
-	Commentator string `gorm:"type:varchar(20);not null" json:"commentator"`
-	Content     string `gorm:"type:longtext;not null" json:"content"`
-	ArticleID   uint   `gorm:"not null" json:"articleId"`
-	ParentID    uint   `json:"parentId"`
+	Commentator string           `gorm:"type:varchar(20);not null" json:"commentator"`
+	Content     string           `gorm:"type:longtext;not null" json:"content"`
+	ArticleID   uint             `gorm:"not null" json:"articleId"`
+	ParentID    uint             `json:"parentId"`
+	Reactions   map[string]int64 `gorm:"-" json:"reactions"`
 }
 
-// CreateArticle 添加文章
-func CreateArticle(data *Article) error {
-	if err := db.Create(&data).Error; err != nil {
+// CreateArticle 添加文章，并记录作者
+func CreateArticle(data *Article, authorID uint) error {
+	return createArticle(data, authorID, nil)
+}
+
+// createArticle 添加文章，after 不为空时在同一事务中执行，用于写入与文章一起提交的关联记录 (如导入来源)
+func createArticle(data *Article, authorID uint, after func(tx *gorm.DB) error) error {
+	if err := prepareVisibility(data, ""); err != nil {
 		return err
 	}
+	original := data.Content
+	cleaned, sanitized := sanitizeArticleContent(original)
+	data.Content = cleaned
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		slug, err := resolveSlug(tx, SlugTargetArticle, data.Slug, data.Title, 0, "")
+		if err != nil {
+			return err
+		}
+		data.Slug = slug
+
+		tags, err := findOrCreateTags(tx, TagNames(data.Tags))
+		if err != nil {
+			return err
+		}
+		data.Tags = tags
+
+		if err := tx.Create(&data).Error; err != nil {
+			return err
+		}
+		if sanitized {
+			if err := recordSanitize(tx, SanitizeTargetArticle, data.ID, original, cleaned); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(&UserArticle{ArticleId: data.ID, UserId: authorID}).Error; err != nil {
+			return err
+		}
+		if after != nil {
+			return after(tx)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	searchEngine.update(data.ID)
+	invalidateContentCache()
 	return nil
 }
 
@@ -44,14 +109,23 @@ func CreateComment(articleId uint, data *Comment) error {
 		return err
 	}
 	data.ArticleID = articleId
-	if err := db.Create(&data).Error; err != nil {
-		return err
-	}
-	return nil
+
+	original := data.Content
+	cleaned, sanitized := sanitizeCommentContent(original)
+	data.Content = cleaned
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&data).Error; err != nil {
+			return err
+		}
+		if sanitized {
+			return recordSanitize(tx, SanitizeTargetComment, data.ID, original, cleaned)
+		}
+		return nil
+	})
 }
 
-// GetCommentsByArticleId 查询文章下的所有评论
-func GetCommentsByArticleId(id uint) ([]Comment, error) {
+// GetCommentsByArticleId 查询文章下的所有评论，访问者需要能够查看该文章
+func GetCommentsByArticleId(id uint, v Viewer, unlock string) ([]Comment, error) {
 	var article Article
 	if err := db.First(&article, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -59,6 +133,9 @@ func GetCommentsByArticleId(id uint) ([]Comment, error) {
 		}
 		return nil, err
 	}
+	if err := checkVisible(&article, v, unlock); err != nil {
+		return nil, err
+	}
 
 	var comments []Comment
 	err := db.Where("article_id = ?", id).Find(&comments).Error
@@ -70,6 +147,11 @@ func GetCommentsByArticleId(id uint) ([]Comment, error) {
 		return nil, errmsg.ErrArticleNoComment // 200
 	}
 
+	if err := fillCommentReactions(comments); err != nil {
+		return nil, err
+	}
+	sanitizeComments(comments)
+
 	return comments, nil
 }
 
@@ -81,11 +163,11 @@ func DeleteComment(id uint) error {
 	return nil
 }
 
-// GetArticles 分页查询文章列表
-func GetArticles(title string, pageSize int, pageNum int) ([]Article, int, error) {
+// GetArticles 分页查询访问者可见的文章列表，置顶文章在前
+func GetArticles(v Viewer, title string, sort string, pageSize int, pageNum int) ([]Article, int, error) {
 	var articles []Article
 	var total int64
-	DB := db.Model(&Article{})
+	DB := filterVisible(db.Model(&Article{}), v)
 
 	if title != "" {
 		DB = DB.Where("title LIKE ?", "%"+title+"%")
@@ -95,16 +177,18 @@ func GetArticles(title string, pageSize int, pageNum int) ([]Article, int, error
 		return nil, 0, err
 	}
 
-	err := DB.Limit(pageSize).Offset((pageNum - 1) * pageSize).Preload("Category").Find(&articles).Error
+	err := orderArticles(DB, sort, true).Limit(pageSize).Offset((pageNum - 1) * pageSize).Preload("Category").Preload("Tags").Find(&articles).Error
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, 0, err
 	}
+	fillViewCounts(articles)
+	hideLocked(articles, v)
 
 	return articles, int(total), nil
 }
 
-// GetCateArticle 查询分类下的所有文章
-func GetCateArticle(id uint, pageSize int, pageNum int) ([]Article, int64, error) {
+// GetCateArticle 查询分类下访问者可见的文章，置顶文章在前
+func GetCateArticle(v Viewer, id uint, sort string, pageSize int, pageNum int) ([]Article, int64, error) {
 	var cate Category
 	if err := db.First(&cate, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -115,34 +199,82 @@ func GetCateArticle(id uint, pageSize int, pageNum int) ([]Article, int64, error
 
 	var cateArticleList []Article
 	var total int64
-	DB := db.Model(&Article{}).Where("cid = ?", id)
+	DB := filterVisible(db.Model(&Article{}).Where("cid = ?", id), v)
 
 	if err := DB.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	err := DB.Limit(pageSize).Offset((pageNum - 1) * pageSize).Find(&cateArticleList).Error
+	err := orderArticles(DB, sort, true).Limit(pageSize).Offset((pageNum - 1) * pageSize).Preload("Tags").Find(&cateArticleList).Error
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, 0, err
 	}
+	fillViewCounts(cateArticleList)
+	hideLocked(cateArticleList, v)
 
 	return cateArticleList, total, nil
 }
 
-// GetArticleInfo 查询单个文章详细信息
-func GetArticleInfo(id uint) (*Article, error) {
+// GetFeaturedArticles 查询匿名访问者可见的精选文章，用于首页轮播
+func GetFeaturedArticles(limit int) ([]Article, error) {
+	var articles []Article
+	DB := filterVisible(db.Model(&Article{}).Where("featured = ?", true), Viewer{})
+	err := orderArticles(DB, SortNewest, false).
+		Limit(limit).Preload("Category").Preload("Tags").Omit("content").Find(&articles).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	fillViewCounts(articles)
+	return articles, nil
+}
+
+// GetArticleInfo 查询单个文章详细信息，unlock 是受密码保护文章的解锁凭证
+func GetArticleInfo(id uint, v Viewer, unlock string) (*Article, error) {
 	var article Article
-	err := db.Preload("Category").Preload("Comments").First(&article, id).Error
+	err := db.Preload("Category").Preload("Tags").Preload("Comments").First(&article, id).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errmsg.ErrArticleNotExist
 		}
 		return nil, err
 	}
+	if err := checkVisible(&article, v, unlock); err != nil {
+		return nil, err
+	}
+	article.ViewCount += pendingViews([]uint{article.ID})[article.ID]
+
+	counts, err := reactionCounts(ReactionTargetArticle, []uint{article.ID})
+	if err != nil {
+		return nil, err
+	}
+	article.Reactions = counts[article.ID]
+	if err := fillCommentReactions(article.Comments); err != nil {
+		return nil, err
+	}
+	sanitizeComments(article.Comments)
+	if err := fillRendered(&article); err != nil {
+		return nil, err
+	}
+	if article.Series, err = GetArticleSeries(article.ID, v); err != nil {
+		return nil, err
+	}
 	return &article, nil
 }
 
-// EditArticle 编辑文章信息
+// FindArticleIDBySlug 根据 slug 查找文章ID
+func FindArticleIDBySlug(slug string) (uint, error) {
+	var article Article
+	if err := db.Select("id").Where("slug = ?", slug).First(&article).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, errmsg.ErrArticleNotExist
+		}
+		return 0, err
+	}
+	return article.ID, nil
+}
+
+// EditArticle 编辑文章信息。data.Version 为编辑所基于的版本 (为 0 时不校验)，
+// 与当前版本不一致时返回 *VersionConflict；编辑成功后 data.Version 更新为新版本
 func EditArticle(id uint, data *Article) error {
 	var article Article
 	if err := db.First(&article, id).Error; err != nil {
@@ -152,45 +284,91 @@ func EditArticle(id uint, data *Article) error {
 		return err
 	}
 
-	updates := map[string]any{
-		"title":   data.Title,
-		"cid":     data.Cid,
-		"desc":    data.Desc,
-		"content": data.Content,
-		"img":     data.Img,
-	}
-	if err := db.Model(&Article{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+	if err := prepareVisibility(data, article.Password); err != nil {
 		return err
 	}
-	return nil
-}
-
-// DeleteArticle 删除文章 (使用事务)
-func DeleteArticle(id uint) error {
-	return db.Transaction(func(tx *gorm.DB) error {
-		var article Article
-		if err := tx.First(&article, id).Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return errmsg.ErrArticleNotExist
+	cleaned, sanitized := sanitizeArticleContent(data.Content)
+	updates := map[string]any{
+		"title":        data.Title,
+		"cid":          data.Cid,
+		"desc":         data.Desc,
+		"content":      cleaned,
+		"img":          data.Img,
+		"pinned":       data.Pinned,
+		"featured":     data.Featured,
+		"sort_weight":  data.SortWeight,
+		"visibility":   data.Visibility,
+		"visible_role": data.VisibleRole,
+		"password":     data.Password,
+		"draft":        data.Draft,
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if sanitized {
+			if err := recordSanitize(tx, SanitizeTargetArticle, id, data.Content, cleaned); err != nil {
+				return err
 			}
-			return err
 		}
 
-		// 删除文章下的所有评论
-		if err := tx.Where("article_id = ?", id).Delete(&Comment{}).Error; err != nil {
+		slug, err := resolveSlug(tx, SlugTargetArticle, data.Slug, data.Title, id, article.Slug)
+		if err != nil {
+			return err
+		}
+		if err := recordSlugChange(tx, SlugTargetArticle, id, article.Slug, slug); err != nil {
 			return err
 		}
+		updates["slug"] = slug
 
-		// 删除文章本身
-		if err := tx.Delete(&Article{}, id).Error; err != nil {
+		version, err := updateVersioned(tx, &Article{}, "id = ?", id, data.Version, updates)
+		if err != nil {
 			return err
 		}
+		data.Version = version
 
-		// 如果有其他关联表（如 UserArticle），也应在此处删除
-		if err := tx.Where("article_id = ?", id).Delete(&UserArticle{}).Error; err != nil {
+		tags, err := findOrCreateTags(tx, TagNames(data.Tags))
+		if err != nil {
 			return err
 		}
+		return tx.Model(&article).Association("Tags").Replace(tags)
+	})
+	if err != nil {
+		return err
+	}
 
-		return nil
+	searchEngine.update(id)
+	invalidateContentCache()
+	return nil
+}
+
+// DeleteArticle 删除文章 (使用事务)，文章和评论移入回收站，可通过 RestoreTrash 恢复
+func DeleteArticle(id uint) error {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		return deleteArticle(tx, id)
 	})
+	if err != nil {
+		return err
+	}
+
+	searchEngine.remove(id)
+	invalidateContentCache()
+	return nil
+}
+
+// deleteArticle 在事务中将文章及其评论移入回收站
+func deleteArticle(tx *gorm.DB, id uint) error {
+	var article Article
+	if err := tx.Select("id").First(&article, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errmsg.ErrArticleNotExist
+		}
+		return err
+	}
+
+	// 删除文章下的所有评论，评论与文章使用相同的删除时间，恢复文章时据此找回一并删除的评论
+	now := time.Now()
+	if err := tx.Model(&Comment{}).Where("article_id = ?", id).UpdateColumn("deleted_at", now).Error; err != nil {
+		return err
+	}
+
+	// 删除文章本身。作者等关联记录保留到彻底删除时再清理，以便恢复
+	return tx.Model(&article).UpdateColumn("deleted_at", now).Error
 }
@@ -13,7 +13,8 @@
 // --- 注册与激活 ---
 
 // RegisterUser 在一个事务中处理完整的用户注册流程
-func RegisterUser(data *User) error {
+// inviteCode 为可选的邀请码，在 invite 模式下必填
+func RegisterUser(data *User, inviteCode string) error {
 	activationLinkTpl := "http://localhost%s/api/v1/active?code=%s"
 
 	return db.Transaction(func(tx *gorm.DB) error {
@@ -21,6 +22,10 @@ func RegisterUser(data *User) error {
 			return err
 		}
 
+		if err := checkRegisterAllowed(tx, data, inviteCode); err != nil {
+			return err
+		}
+
 		hashedPassword, err := HashPassword(data.Password)
 		if err != nil {
 			return err
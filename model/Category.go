@@ -8,8 +8,10 @@
 )
 
 type Category struct {
-	ID   uint   `gorm:"primary_key;auto_increment" json:"id"`
-	Name string `gorm:"type:varchar(20);not null;unique" json:"name"`
+	ID      uint   `gorm:"primary_key;auto_increment" json:"id"`
+	Name    string `gorm:"type:varchar(20);not null;unique" json:"name"`
+	Slug    string `gorm:"type:varchar(100);not null;default:''" json:"slug"`
+	Version uint   `gorm:"not null;default:1" json:"version"` // 乐观锁版本号，每次编辑加一
 }
 
 // CheckCategoryExists 检查分类名是否存在
@@ -36,7 +38,21 @@ func CreateCategory(data *Category) error {
 		return errmsg.ErrCateNameUsed // 分类名已存在
 	}
 
-	return db.Create(&data).Error
+	err = db.Transaction(func(tx *gorm.DB) error {
+		slug, err := resolveSlug(tx, SlugTargetCategory, data.Slug, data.Name, 0, "")
+		if err != nil {
+			return err
+		}
+		data.Slug = slug
+		return tx.Create(&data).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	// 新分类需要出现在站点地图中
+	invalidateContentCache()
+	return nil
 }
 
 // GetCategory 分页查询分类列表
@@ -78,9 +94,31 @@ func EditCategory(id uint, data *Category) error {
 		return errmsg.ErrCateNameUsed // 新名称已被其他分类使用
 	}
 
-	// 使用 map 更新
-	updates := map[string]any{"name": data.Name}
-	return db.Model(&Category{}).Where("id = ?", id).Updates(updates).Error
+	err = db.Transaction(func(tx *gorm.DB) error {
+		slug, err := resolveSlug(tx, SlugTargetCategory, data.Slug, data.Name, id, cate.Slug)
+		if err != nil {
+			return err
+		}
+		if err := recordSlugChange(tx, SlugTargetCategory, id, cate.Slug, slug); err != nil {
+			return err
+		}
+
+		// 使用 map 更新，data.Version 为编辑所基于的版本
+		updates := map[string]any{"name": data.Name, "slug": slug}
+		version, err := updateVersioned(tx, &Category{}, "id = ?", id, data.Version, updates)
+		if err != nil {
+			return err
+		}
+		data.Version = version
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// 分类名会出现在订阅源、站点地图等缓存内容中
+	invalidateContentCache()
+	return nil
 }
 
 // DeleteCategory 删除分类
@@ -96,7 +134,12 @@ func DeleteCategory(id uint) error {
 	// TODO: 在这里可以添加业务逻辑，例如，如果分类下有文章，是否允许删除
 	// ...
 
-	return db.Where("id = ?", id).Delete(&Category{}).Error
+	if err := db.Where("id = ?", id).Delete(&Category{}).Error; err != nil {
+		return err
+	}
+
+	invalidateContentCache()
+	return nil
 }
 
 // FindCategoryById 根据id查找分类
@@ -112,3 +155,16 @@ func FindCategoryById(id uint) (*Category, error) {
 	}
 	return &cate, nil
 }
+
+// FindCategoryBySlug 根据 slug 查找分类
+func FindCategoryBySlug(slug string) (*Category, error) {
+	var cate Category
+	err := db.Where("slug = ?", slug).First(&cate).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmsg.ErrCateNotExist
+		}
+		return nil, err
+	}
+	return &cate, nil
+}
@@ -0,0 +1,122 @@
+package model
+
+import (
+	"errors"
+	"goblog/utils"
+	"goblog/utils/errmsg"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Invitation struct {
+	BaseModel
+	Code      string     `gorm:"type:varchar(40);not null;uniqueIndex" json:"code"`
+	CreatedBy uint       `gorm:"not null;index" json:"createdBy"`
+	MaxUses   int        `gorm:"not null;default:1" json:"maxUses"`
+	UsedCount int        `gorm:"not null;default:0" json:"usedCount"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// CreateInvitation 生成一个新的邀请码
+func CreateInvitation(data *Invitation) error {
+	data.Code = strings.ReplaceAll(utils.CreateUUID(), "-", "")
+	return db.Create(data).Error
+}
+
+// GetInvitations 分页查询邀请码列表
+func GetInvitations(pageSize int, pageNum int) ([]Invitation, int64, error) {
+	var invitations []Invitation
+	var total int64
+
+	if err := db.Model(&Invitation{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := db.Order("id desc").Limit(pageSize).Offset((pageNum - 1) * pageSize).Find(&invitations).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, err
+	}
+
+	return invitations, total, nil
+}
+
+// DeleteInvitation 删除 (作废) 邀请码
+func DeleteInvitation(id uint) error {
+	result := db.Delete(&Invitation{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errmsg.ErrInviteNotExist
+	}
+	return nil
+}
+
+// useInvitation 在事务中消耗一次邀请码，返回邀请人的用户ID (内部函数)
+func useInvitation(tx *gorm.DB, code string) (uint, error) {
+	var invitation Invitation
+	if err := tx.Where("code = ?", code).First(&invitation).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, errmsg.ErrInviteNotExist
+		}
+		return 0, err
+	}
+	if invitation.ExpiresAt != nil && invitation.ExpiresAt.Before(time.Now()) {
+		return 0, errmsg.ErrInviteExpired
+	}
+
+	// 使用条件更新保证并发注册时不会超出使用次数
+	result := tx.Model(&Invitation{}).
+		Where("id = ? AND used_count < max_uses", invitation.ID).
+		Update("used_count", gorm.Expr("used_count + 1"))
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, errmsg.ErrInviteUsedUp
+	}
+	return invitation.CreatedBy, nil
+}
+
+// checkRegisterAllowed 根据注册模式校验是否允许注册，并在提供邀请码时消耗它 (内部函数)
+func checkRegisterAllowed(tx *gorm.DB, data *User, inviteCode string) error {
+	switch utils.RegisterMode {
+	case utils.RegisterModeClosed:
+		return errmsg.ErrRegisterClosed
+	case utils.RegisterModeInvite:
+		if inviteCode == "" {
+			return errmsg.ErrInviteRequired
+		}
+	case utils.RegisterModeDomain:
+		// 邮箱域名在白名单内，或持有有效邀请码均可注册
+		if inviteCode == "" && !isAllowedDomain(data.Email) {
+			return errmsg.ErrEmailDomainNotAllowed
+		}
+	}
+
+	if inviteCode == "" {
+		return nil
+	}
+	inviter, err := useInvitation(tx, inviteCode)
+	if err != nil {
+		return err
+	}
+	data.InvitedBy = inviter
+	return nil
+}
+
+// isAllowedDomain 判断邮箱域名是否在注册白名单中
+func isAllowedDomain(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+	for _, allowed := range utils.AllowedDomains {
+		if strings.ToLower(allowed) == domain {
+			return true
+		}
+	}
+	return false
+}
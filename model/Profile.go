@@ -10,15 +10,16 @@
 
 type Profile struct {
 	BaseModel
-	UserID uint   `gorm:"not null;uniqueIndex;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"userId"` // OnDelete: 当在user中被删除，profile自动删除
-	Name   string `gorm:"type:varchar(50)" json:"name"`
-	Desc   string `gorm:"type:varchar(200)" json:"desc"`
-	QqChat string `gorm:"type:varchar(32)" json:"qqchat"`
-	WeChat string `gorm:"type:varchar(50)" json:"wechat"`
-	Weibo  string `gorm:"type:varchar(50)" json:"weibo"`
-	Email  string `gorm:"type:varchar(32)" json:"email"`
-	Img    string `gorm:"type:varchar(255)" json:"img"`
-	Avatar string `gorm:"type:varchar(255)" json:"avatar"`
+	Version uint   `gorm:"not null;default:1" json:"version"`                                                // 乐观锁版本号，每次编辑加一
+	UserID  uint   `gorm:"not null;uniqueIndex;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"userId"` // OnDelete: 当在user中被删除，profile自动删除
+	Name    string `gorm:"type:varchar(50)" json:"name"`
+	Desc    string `gorm:"type:varchar(200)" json:"desc"`
+	QqChat  string `gorm:"type:varchar(32)" json:"qqchat"`
+	WeChat  string `gorm:"type:varchar(50)" json:"wechat"`
+	Weibo   string `gorm:"type:varchar(50)" json:"weibo"`
+	Email   string `gorm:"type:varchar(32)" json:"email"`
+	Img     string `gorm:"type:varchar(255)" json:"img"`
+	Avatar  string `gorm:"type:varchar(255)" json:"avatar"`
 }
 
 // GetProfileByUserID 根据用户ID获取个人信息
@@ -34,8 +35,9 @@ func GetProfileByUserID(userID uint) (*Profile, error) {
 	return &profile, nil
 }
 
-// UpdateProfileByUserID 根据用户ID更新个人信息
-func UpdateProfileByUserID(userID uint, req *dto.ReqUpdateProfile) error {
+// UpdateProfileByUserID 根据用户ID更新个人信息，返回更新后的版本号。
+// req.Version 为编辑所基于的版本 (为 0 时不校验)，与当前版本不一致时返回 *VersionConflict
+func UpdateProfileByUserID(userID uint, req *dto.ReqUpdateProfile) (uint, error) {
 	updates := map[string]any{
 		"name":   req.Name,
 		"desc":   req.Desc,
@@ -45,13 +47,9 @@ func UpdateProfileByUserID(userID uint, req *dto.ReqUpdateProfile) error {
 		"avatar": req.Avatar,
 	}
 
-	result := db.Model(&Profile{}).Where("user_id = ?", userID).Updates(updates)
-	if result.Error != nil {
-		return result.Error
+	version, err := updateVersioned(db, &Profile{}, "user_id = ?", userID, req.Version, updates)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, errmsg.ErrUserNotExist
 	}
-
-	if result.RowsAffected == 0 {
-		return errmsg.ErrUserNotExist
-	}
-	return nil
+	return version, err
 }
@@ -0,0 +1,165 @@
+package model
+
+import (
+	"errors"
+	"goblog/utils"
+	"goblog/utils/errmsg"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 回应的目标类型
+const (
+	ReactionTargetArticle = "article"
+	ReactionTargetComment = "comment"
+)
+
+// ReactionLike 是点赞，匿名用户也可以使用
+const ReactionLike = "like"
+
+// Reaction 记录一次点赞或表情回应。
+// 登录用户以 UserID 去重，匿名用户 (UserID 为 0) 以 Fingerprint 去重
+type Reaction struct {
+	ID          uint      `gorm:"primarykey" json:"id"`
+	CreatedAt   time.Time `json:"createdAt"`
+	TargetType  string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_reaction_unique,priority:1" json:"targetType"`
+	TargetID    uint      `gorm:"not null;uniqueIndex:idx_reaction_unique,priority:2" json:"targetId"`
+	Type        string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_reaction_unique,priority:3" json:"type"`
+	UserID      uint      `gorm:"not null;default:0;uniqueIndex:idx_reaction_unique,priority:4;index" json:"userId"`
+	Fingerprint string    `gorm:"type:varchar(40);not null;default:'';uniqueIndex:idx_reaction_unique,priority:5" json:"-"`
+}
+
+// IsValidReaction 判断回应类型是否可用
+func IsValidReaction(reactionType string) bool {
+	return reactionType == ReactionLike || slices.Contains(utils.ReactionTypes, reactionType)
+}
+
+// checkReaction 校验回应类型、匿名权限以及目标是否存在 (内部函数)
+func checkReaction(r *Reaction) error {
+	if !IsValidReaction(r.Type) {
+		return errmsg.ErrReactionInvalid
+	}
+	if r.UserID == 0 {
+		if r.Type != ReactionLike {
+			return errmsg.ErrReactionNeedLogin
+		}
+	} else {
+		r.Fingerprint = ""
+	}
+
+	var err error
+	switch r.TargetType {
+	case ReactionTargetArticle:
+		err = db.Select("id").First(&Article{}, r.TargetID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errmsg.ErrArticleNotExist
+		}
+	case ReactionTargetComment:
+		err = db.Select("id").First(&Comment{}, r.TargetID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errmsg.ErrCommentNotExist
+		}
+	default:
+		return errmsg.ErrReactionInvalid
+	}
+	return err
+}
+
+// AddReaction 添加回应，重复回应不会报错也不会重复计数
+func AddReaction(r *Reaction) error {
+	if err := checkReaction(r); err != nil {
+		return err
+	}
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(r).Error
+}
+
+// RemoveReaction 取消回应
+func RemoveReaction(r *Reaction) error {
+	if err := checkReaction(r); err != nil {
+		return err
+	}
+	return db.Where(&Reaction{
+		TargetType:  r.TargetType,
+		TargetID:    r.TargetID,
+		Type:        r.Type,
+		UserID:      r.UserID,
+		Fingerprint: r.Fingerprint,
+	}, "target_type", "target_id", "type", "user_id", "fingerprint").Delete(&Reaction{}).Error
+}
+
+// reactionCounts 统计一组目标的各类回应数量
+func reactionCounts(targetType string, ids []uint) (map[uint]map[string]int64, error) {
+	counts := make(map[uint]map[string]int64, len(ids))
+	if len(ids) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		TargetID uint
+		Type     string
+		Count    int64
+	}
+	err := db.Model(&Reaction{}).
+		Select("target_id, type, COUNT(*) AS count").
+		Where("target_type = ? AND target_id IN ?", targetType, ids).
+		Group("target_id, type").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if counts[row.TargetID] == nil {
+			counts[row.TargetID] = map[string]int64{}
+		}
+		counts[row.TargetID][row.Type] = row.Count
+	}
+	return counts, nil
+}
+
+// fillCommentReactions 填充评论的回应数量
+func fillCommentReactions(comments []Comment) error {
+	ids := make([]uint, 0, len(comments))
+	for _, c := range comments {
+		ids = append(ids, c.ID)
+	}
+	counts, err := reactionCounts(ReactionTargetComment, ids)
+	if err != nil {
+		return err
+	}
+	for i := range comments {
+		comments[i].Reactions = counts[comments[i].ID]
+	}
+	return nil
+}
+
+// GetLikedArticles 分页查询用户点赞过且访问者可见的文章，未指定排序方式时按点赞时间倒序
+func GetLikedArticles(v Viewer, userID uint, sort string, pageSize int, pageNum int) ([]Article, int64, error) {
+	var articles []Article
+	var total int64
+	DB := db.Model(&Article{}).
+		Joins("JOIN reaction ON reaction.target_id = article.id AND reaction.target_type = ? AND reaction.type = ?", ReactionTargetArticle, ReactionLike).
+		Where("reaction.user_id = ?", userID)
+	DB = filterVisible(DB, v)
+
+	if err := DB.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if sort == "" {
+		DB = DB.Order("reaction.created_at desc")
+	} else {
+		DB = orderArticles(DB, sort, false)
+	}
+	err := DB.Limit(pageSize).Offset((pageNum - 1) * pageSize).
+		Preload("Category").Preload("Tags").Find(&articles).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, err
+	}
+	fillViewCounts(articles)
+	hideLocked(articles, v)
+	return articles, total, nil
+}
@@ -0,0 +1,275 @@
+package model
+
+import (
+	"errors"
+	"goblog/utils/errmsg"
+
+	"gorm.io/gorm"
+)
+
+// Series 是由多篇文章按顺序组成的系列 (例如分多篇发布的教程)
+type Series struct {
+	BaseModel
+	Title string       `gorm:"type:varchar(100);not null" json:"title"`
+	Desc  string       `gorm:"type:varchar(200)" json:"desc"`
+	Parts []SeriesPart `gorm:"-" json:"parts,omitempty"`
+}
+
+// SeriesArticle 记录文章所属的系列及其在系列中的位置，一篇文章最多属于一个系列
+type SeriesArticle struct {
+	SeriesID  uint `gorm:"not null;index" json:"seriesId"`
+	ArticleID uint `gorm:"primaryKey;autoIncrement:false" json:"articleId"`
+	Position  int  `gorm:"not null" json:"position"`
+}
+
+// SeriesPart 是系列中的一篇文章
+type SeriesPart struct {
+	ID       uint   `json:"id"`
+	Title    string `json:"title"`
+	Slug     string `json:"slug"`
+	Position int    `json:"position"`
+
+	// 用于按访问者过滤
+	Visibility  string `json:"-"`
+	VisibleRole int    `json:"-"`
+	Draft       bool   `json:"-"`
+}
+
+// SeriesNav 是文章详情中的系列导航: 所属系列、上一篇、下一篇和系列目录
+type SeriesNav struct {
+	ID       uint         `json:"id"`
+	Title    string       `json:"title"`
+	Position int          `json:"position"`
+	Prev     *SeriesPart  `json:"prev"`
+	Next     *SeriesPart  `json:"next"`
+	Parts    []SeriesPart `json:"parts"`
+}
+
+// CreateSeries 创建系列
+func CreateSeries(data *Series) error {
+	return db.Create(data).Error
+}
+
+// GetSeriesList 分页查询系列列表
+func GetSeriesList(pageSize int, pageNum int) ([]Series, int64, error) {
+	var list []Series
+	var total int64
+
+	if err := db.Model(&Series{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := db.Order("id desc").Limit(pageSize).Offset((pageNum - 1) * pageSize).Find(&list).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, err
+	}
+	return list, total, nil
+}
+
+// GetSeries 查询系列详情及按顺序排列的访问者可见的文章
+func GetSeries(id uint, v Viewer) (*Series, error) {
+	var series Series
+	if err := db.First(&series, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmsg.ErrSeriesNotExist
+		}
+		return nil, err
+	}
+
+	parts, err := seriesParts(db, id, v, 0)
+	if err != nil {
+		return nil, err
+	}
+	series.Parts = parts
+	return &series, nil
+}
+
+// EditSeries 编辑系列信息
+func EditSeries(id uint, data *Series) error {
+	result := db.Model(&Series{}).Where("id = ?", id).Updates(map[string]any{"title": data.Title, "desc": data.Desc})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		// 内容未变化时 RowsAffected 也为 0，需要区分系列是否存在
+		if _, err := findSeries(db, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteSeries 删除系列，文章本身不受影响
+func DeleteSeries(id uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if _, err := findSeries(tx, id); err != nil {
+			return err
+		}
+		if err := tx.Where("series_id = ?", id).Delete(&SeriesArticle{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&Series{}, id).Error
+	})
+}
+
+// AddSeriesArticle 将文章追加到系列末尾
+func AddSeriesArticle(seriesID uint, articleID uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if _, err := findSeries(tx, seriesID); err != nil {
+			return err
+		}
+		// seriesID 传 0: 文章已在任何系列中 (包括当前系列) 都不能再追加
+		if err := checkSeriesArticles(tx, 0, []uint{articleID}); err != nil {
+			return err
+		}
+
+		var last struct{ Position int }
+		if err := tx.Model(&SeriesArticle{}).Select("COALESCE(MAX(position), 0) AS position").
+			Where("series_id = ?", seriesID).Scan(&last).Error; err != nil {
+			return err
+		}
+		return tx.Create(&SeriesArticle{SeriesID: seriesID, ArticleID: articleID, Position: last.Position + 1}).Error
+	})
+}
+
+// RemoveSeriesArticle 将文章移出系列，其后的文章依次前移
+func RemoveSeriesArticle(seriesID uint, articleID uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var member SeriesArticle
+		if err := tx.Where("series_id = ? AND article_id = ?", seriesID, articleID).First(&member).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errmsg.ErrSeriesArticleNotIn
+			}
+			return err
+		}
+		if err := tx.Delete(&member).Error; err != nil {
+			return err
+		}
+		return tx.Model(&SeriesArticle{}).Where("series_id = ? AND position > ?", seriesID, member.Position).
+			Update("position", gorm.Expr("position - 1")).Error
+	})
+}
+
+// SetSeriesArticles 按给定顺序设置系列包含的文章，可用于调整顺序、批量添加或移除
+func SetSeriesArticles(seriesID uint, articleIDs []uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if _, err := findSeries(tx, seriesID); err != nil {
+			return err
+		}
+		seen := make(map[uint]bool, len(articleIDs))
+		for _, id := range articleIDs {
+			if seen[id] {
+				return errmsg.ErrInvalidParams.WithMsg("文章 %d 重复出现", id)
+			}
+			seen[id] = true
+		}
+		if err := checkSeriesArticles(tx, seriesID, articleIDs); err != nil {
+			return err
+		}
+
+		if err := tx.Where("series_id = ?", seriesID).Delete(&SeriesArticle{}).Error; err != nil {
+			return err
+		}
+		members := make([]SeriesArticle, 0, len(articleIDs))
+		for i, id := range articleIDs {
+			members = append(members, SeriesArticle{SeriesID: seriesID, ArticleID: id, Position: i + 1})
+		}
+		if len(members) == 0 {
+			return nil
+		}
+		return tx.Create(&members).Error
+	})
+}
+
+// GetArticleSeries 返回文章所属系列的导航信息，文章不属于任何系列时返回 nil。
+// 目录只包含访问者可见的文章，调用方需已确认访问者可以查看该文章
+func GetArticleSeries(articleID uint, v Viewer) (*SeriesNav, error) {
+	var member SeriesArticle
+	if err := db.Where("article_id = ?", articleID).First(&member).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	series, err := findSeries(db, member.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+	parts, err := seriesParts(db, series.ID, v, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	nav := &SeriesNav{ID: series.ID, Title: series.Title, Parts: parts}
+	for i, p := range parts {
+		if p.ID != articleID {
+			continue
+		}
+		nav.Position = p.Position
+		if i > 0 {
+			nav.Prev = &parts[i-1]
+		}
+		if i < len(parts)-1 {
+			nav.Next = &parts[i+1]
+		}
+	}
+	return nav, nil
+}
+
+// findSeries 查询系列，不存在时返回 ErrSeriesNotExist
+func findSeries(tx *gorm.DB, id uint) (*Series, error) {
+	var series Series
+	if err := tx.First(&series, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmsg.ErrSeriesNotExist
+		}
+		return nil, err
+	}
+	return &series, nil
+}
+
+// checkSeriesArticles 检查文章是否都存在，且不属于 seriesID 以外的系列
+func checkSeriesArticles(tx *gorm.DB, seriesID uint, articleIDs []uint) error {
+	if len(articleIDs) == 0 {
+		return nil
+	}
+	var count int64
+	if err := tx.Model(&Article{}).Where("id IN ?", articleIDs).Count(&count).Error; err != nil {
+		return err
+	}
+	if count != int64(len(articleIDs)) {
+		return errmsg.ErrArticleNotExist
+	}
+
+	var other SeriesArticle
+	err := tx.Where("article_id IN ? AND series_id != ?", articleIDs, seriesID).First(&other).Error
+	if err == nil {
+		return errmsg.ErrSeriesArticleUsed.WithMsg("文章 %d 已属于系列 %d", other.ArticleID, other.SeriesID)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+// seriesParts 按顺序返回系列中未删除且访问者可见的文章。current 为正在查看的文章，
+// 访问者能查看它 (如作者查看自己的草稿) 时即使不在列表可见范围内也保留，使导航能定位当前文章
+func seriesParts(tx *gorm.DB, seriesID uint, v Viewer, current uint) ([]SeriesPart, error) {
+	var all []SeriesPart
+	err := tx.Table("series_article").
+		Select("article.id, article.title, article.slug, series_article.position, article.visibility, article.visible_role, article.draft").
+		Joins("JOIN article ON article.id = series_article.article_id AND article.deleted_at IS NULL").
+		Where("series_article.series_id = ?", seriesID).
+		Order("series_article.position").
+		Scan(&all).Error
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]SeriesPart, 0, len(all))
+	for _, p := range all {
+		if p.ID == current || v.canList(p.Visibility, p.VisibleRole, p.Draft) {
+			parts = append(parts, p)
+		}
+	}
+	return parts, nil
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type Tag struct {
+	ID   uint   `gorm:"primary_key;auto_increment" json:"id"`
+	Name string `gorm:"type:varchar(20);not null;unique" json:"name"`
+}
+
+// TagCount 是标签及其文章数量
+type TagCount struct {
+	Tag
+	Count int64 `json:"count"`
+}
+
+// findOrCreateTags 根据标签名查找标签，不存在的标签会被创建 (内部函数)
+func findOrCreateTags(tx *gorm.DB, names []string) ([]Tag, error) {
+	tags := make([]Tag, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		var tag Tag
+		if err := tx.Where(Tag{Name: name}).FirstOrCreate(&tag).Error; err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// GetTags 查询所有标签及其文章数量
+func GetTags() ([]TagCount, error) {
+	var tags []TagCount
+	err := db.Model(&Tag{}).
+		Select("tag.id, tag.name, COUNT(article.id) AS count").
+		Joins("LEFT JOIN article_tag ON article_tag.tag_id = tag.id").
+		Joins("LEFT JOIN article ON article.id = article_tag.article_id AND article.deleted_at IS NULL").
+		Group("tag.id, tag.name").
+		Order("count desc").
+		Scan(&tags).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// TagNames 返回标签名列表
+func TagNames(tags []Tag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
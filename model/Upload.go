@@ -6,7 +6,7 @@
 	"context"
 	"goblog/utils"
 	"goblog/utils/errmsg"
-	"mime/multipart"
+	"io"
 
 	"github.com/qiniu/go-sdk/v7/auth/qbox"
 	"github.com/qiniu/go-sdk/v7/storage"
@@ -21,7 +21,7 @@
 )
 
 // UploadFile 将文件上传到七牛云
-func UploadFile(file multipart.File, fileSize int64) (string, error) {
+func UploadFile(file io.Reader, fileSize int64) (string, error) {
 	putPolicy := storage.PutPolicy{
 		Scope: Bucket,
 	}
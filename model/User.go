@@ -16,13 +16,14 @@
 
 type User struct {
 	BaseModel
-	Username string  `gorm:"type:varchar(20);not null;uniqueIndex" json:"username"`
-	Password string  `gorm:"type:varchar(128);not null" json:"-"`
-	Email    string  `gorm:"type:varchar(32);not null;uniqueIndex" json:"email"`
-	Role     int     `gorm:"type:int;DEFAULT:2" json:"role"`
-	Status   string  `gorm:"type:varchar(12);default:'N'" json:"-"`
-	Code     string  `gorm:"type:varchar(80)" json:"-"`
-	Profile  Profile `gorm:"foreignKey:UserID" json:"profile"`
+	Username  string  `gorm:"type:varchar(20);not null;uniqueIndex" json:"username"`
+	Password  string  `gorm:"type:varchar(128);not null" json:"-"`
+	Email     string  `gorm:"type:varchar(32);not null;uniqueIndex" json:"email"`
+	Role      int     `gorm:"type:int;DEFAULT:2" json:"role"`
+	Status    string  `gorm:"type:varchar(12);default:'N'" json:"-"`
+	Code      string  `gorm:"type:varchar(80)" json:"-"`
+	InvitedBy uint    `gorm:"index" json:"invitedBy"`
+	Profile   Profile `gorm:"foreignKey:UserID" json:"profile"`
 }
 
 const (
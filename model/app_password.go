@@ -0,0 +1,107 @@
+package model
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"goblog/utils/errmsg"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// appPasswordBytes 是应用密码的随机字节数，编码后为 24 个字符
+const appPasswordBytes = 15
+
+// appPasswordEncoding 使用小写且不含易混淆字符的字母表，便于在桌面客户端中手动输入
+var appPasswordEncoding = base32.NewEncoding("abcdefghijkmnpqrstuvwxyz23456789").WithPadding(base32.NoPadding)
+
+// AppPassword 是用户为桌面写作客户端 (MetaWeblog/XML-RPC) 生成的应用密码。
+// 应用密码只能用于这些接口，不能登录后台，可以单独撤销；数据库中只保存其哈希
+type AppPassword struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UserID     uint       `gorm:"not null;index" json:"-"`
+	Name       string     `gorm:"type:varchar(50);not null" json:"name"`           // 用途，如使用的客户端名称
+	Hash       string     `gorm:"type:char(64);not null;uniqueIndex" json:"-"`     // 密码的 SHA-256
+	Hint       string     `gorm:"type:varchar(8);not null;default:''" json:"hint"` // 密码的前几位，用于辨认
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+}
+
+// hashAppPassword 计算应用密码的哈希，忽略大小写和客户端中为便于阅读而保留的空格
+func hashAppPassword(password string) string {
+	password = strings.ToLower(strings.Join(strings.Fields(password), ""))
+	sum := sha256.Sum256([]byte(password))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateAppPassword 为用户生成应用密码，返回的明文密码只在创建时可见
+func CreateAppPassword(userID uint, name string) (*AppPassword, string, error) {
+	secret := make([]byte, appPasswordBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	plain := appPasswordEncoding.EncodeToString(secret)
+
+	// 每 4 个字符一组，与常见博客系统的应用密码格式一致
+	var groups []string
+	for i := 0; i < len(plain); i += 4 {
+		groups = append(groups, plain[i:min(i+4, len(plain))])
+	}
+	data := &AppPassword{UserID: userID, Name: name, Hash: hashAppPassword(plain), Hint: plain[:4]}
+	if err := db.Create(data).Error; err != nil {
+		return nil, "", err
+	}
+	return data, strings.Join(groups, " "), nil
+}
+
+// GetAppPasswords 查询用户的所有应用密码
+func GetAppPasswords(userID uint) ([]AppPassword, error) {
+	var passwords []AppPassword
+	err := db.Where("user_id = ?", userID).Order("id DESC").Find(&passwords).Error
+	return passwords, err
+}
+
+// DeleteAppPassword 撤销用户的应用密码
+func DeleteAppPassword(userID uint, id uint) error {
+	result := db.Where("user_id = ?", userID).Delete(&AppPassword{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errmsg.ErrAppPasswordNotExist
+	}
+	return nil
+}
+
+// CheckAppPassword 使用用户名和应用密码验证用户，用于 XML-RPC 等不支持 Token 的接口
+func CheckAppPassword(username string, password string) (*User, error) {
+	var user User
+	if err := db.Where("username = ?", username).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmsg.ErrAppPasswordWrong
+		}
+		return nil, err
+	}
+
+	var ap AppPassword
+	err := db.Where("user_id = ? AND hash = ?", user.ID, hashAppPassword(password)).First(&ap).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmsg.ErrAppPasswordWrong
+		}
+		return nil, err
+	}
+	if user.Status != "Y" {
+		return nil, errmsg.ErrEmailNotActive
+	}
+
+	now := time.Now()
+	if err := db.Model(&ap).Update("last_used_at", &now).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"goblog/utils/errmsg"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ArchiveMonth 是某个月份的文章数量
+type ArchiveMonth struct {
+	Month int   `json:"month"`
+	Count int64 `json:"count"`
+}
+
+// ArchiveYear 是某一年的文章数量及各月份明细，月份从新到旧排列
+type ArchiveYear struct {
+	Year   int            `json:"year"`
+	Count  int64          `json:"count"`
+	Months []ArchiveMonth `json:"months"`
+}
+
+// GetArchives 按年月统计匿名访问者可见的文章数量。统计只需一次分组查询，结果随内容版本号缓存
+func GetArchives() ([]ArchiveYear, error) {
+	key := fmt.Sprintf("archives:v%d", contentVersion())
+	var years []ArchiveYear
+	if getCache(key, &years) {
+		return years, nil
+	}
+
+	var rows []struct {
+		Year  int
+		Month int
+		Count int64
+	}
+	err := filterVisible(db.Model(&Article{}), Viewer{}).
+		Select("YEAR(created_at) AS year, MONTH(created_at) AS month, COUNT(*) AS count").
+		Group("year, month").
+		Order("year DESC, month DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	years = []ArchiveYear{}
+	for _, row := range rows {
+		if len(years) == 0 || years[len(years)-1].Year != row.Year {
+			years = append(years, ArchiveYear{Year: row.Year})
+		}
+		y := &years[len(years)-1]
+		y.Count += row.Count
+		y.Months = append(y.Months, ArchiveMonth{Month: row.Month, Count: row.Count})
+	}
+
+	setCache(key, years, 24*time.Hour)
+	return years, nil
+}
+
+// GetArchiveArticles 分页查询某年某月发布的文章，按发布时间倒序 (归档按时间浏览，不受置顶和排序权重影响)
+func GetArchiveArticles(v Viewer, year int, month int, pageSize int, pageNum int) ([]Article, int64, error) {
+	if year < 1970 || year > 9999 || month < 1 || month > 12 {
+		return nil, 0, errmsg.ErrInvalidArchive
+	}
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.Local)
+	end := start.AddDate(0, 1, 0)
+
+	var articles []Article
+	var total int64
+	DB := filterVisible(db.Model(&Article{}).Where("article.created_at >= ? AND article.created_at < ?", start, end), v)
+
+	if err := DB.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := DB.Order("article.created_at DESC").Order("article.id DESC").Limit(pageSize).Offset((pageNum - 1) * pageSize).
+		Preload("Category").Preload("Tags").Omit("content").Find(&articles).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, err
+	}
+	fillViewCounts(articles)
+	hideLocked(articles, v)
+	return articles, total, nil
+}
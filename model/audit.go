@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AuditLog 记录一次管理操作，供管理员追溯
+type AuditLog struct {
+	ID         uint           `gorm:"primarykey" json:"id"`
+	CreatedAt  time.Time      `gorm:"index" json:"createdAt"`
+	UserID     uint           `gorm:"not null;index" json:"userId"`                      // 操作者
+	Action     string         `gorm:"type:varchar(50);not null;index" json:"action"`     // 操作，如 article.bulk.trash
+	TargetType string         `gorm:"type:varchar(20);not null" json:"targetType"`       // 操作对象类型
+	TargetIDs  []uint         `gorm:"serializer:json;type:text" json:"targetIds"`        // 操作成功的对象
+	Detail     map[string]any `gorm:"serializer:json;type:text" json:"detail,omitempty"` // 操作参数和结果
+}
+
+// recordAudit 在事务中记录一条审计日志
+func recordAudit(tx *gorm.DB, entry *AuditLog) error {
+	return tx.Create(entry).Error
+}
+
+// GetAuditLogs 分页查询审计日志，按时间倒序。action 为空时查询全部，以 "." 结尾时按前缀匹配
+func GetAuditLogs(action string, userID uint, pageSize int, pageNum int) ([]AuditLog, int64, error) {
+	var logs []AuditLog
+	var total int64
+	DB := db.Model(&AuditLog{})
+	if action != "" {
+		if action[len(action)-1] == '.' {
+			DB = DB.Where("action LIKE ?", action+"%")
+		} else {
+			DB = DB.Where("action = ?", action)
+		}
+	}
+	if userID > 0 {
+		DB = DB.Where("user_id = ?", userID)
+	}
+
+	if err := DB.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := DB.Order("id DESC").Limit(pageSize).Offset((pageNum - 1) * pageSize).Find(&logs).Error
+	return logs, total, err
+}
@@ -0,0 +1,192 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goblog/utils"
+	"goblog/utils/errmsg"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Redis 中与自动保存相关的键
+const (
+	autosaveKeyPrefix = "autosave:"      // 自动保存的编辑器状态 (string: JSON)，键为前缀加 "用户ID:文章ID"
+	autosaveDirtyKey  = "autosave:dirty" // 尚未写入数据库的自动保存 (set: 用户ID:文章ID)
+
+	autosaveDiscardPrefix = "autosave:discarded:" // 丢弃自动保存的时间 (string: UnixNano)，键为前缀加 "用户ID:文章ID"
+)
+
+// autosaveDiscardTTL 是丢弃记录的保留时间，只需覆盖一次写入数据库任务的执行时间
+const autosaveDiscardTTL = 10 * time.Minute
+
+// Autosave 是编辑器自动保存的草稿。每个用户的每篇文章只保留最新的一份，新文章的 ArticleID 为 0。
+// 自动保存先写入 Redis，由后台任务定期写入数据库，不会产生文章修订
+type Autosave struct {
+	UserID      uint      `gorm:"primaryKey;autoIncrement:false" json:"userId"`
+	ArticleID   uint      `gorm:"primaryKey;autoIncrement:false" json:"articleId"`
+	BaseVersion uint      `gorm:"not null;default:0" json:"baseVersion"` // 开始编辑时文章的版本号
+	Title       string    `gorm:"type:varchar(100)" json:"title"`
+	Cid         uint      `json:"cid"`
+	Desc        string    `gorm:"type:varchar(200)" json:"desc"`
+	Content     string    `gorm:"type:longtext" json:"content"`
+	Img         string    `gorm:"type:varchar(200)" json:"img"`
+	Tags        []string  `gorm:"serializer:json;type:text" json:"tags"`
+	SavedAt     time.Time `json:"savedAt"`
+	Outdated    bool      `gorm:"-" json:"outdated"` // 文章在自动保存之后已被修改 (版本号变化)，需要合并
+}
+
+func autosaveMember(userID uint, articleID uint) string {
+	return fmt.Sprintf("%d:%d", userID, articleID)
+}
+
+func autosaveKey(member string) string {
+	return autosaveKeyPrefix + member
+}
+
+func autosaveDiscardKey(member string) string {
+	return autosaveDiscardPrefix + member
+}
+
+// SaveAutosave 保存编辑器状态，覆盖该用户对该文章的上一次自动保存
+func SaveAutosave(data *Autosave) error {
+	if data.ArticleID > 0 {
+		if err := db.Select("id").First(&Article{}, data.ArticleID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errmsg.ErrArticleNotExist
+			}
+			return err
+		}
+	}
+	data.SavedAt = time.Now()
+
+	value, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	member := autosaveMember(data.UserID, data.ArticleID)
+	pipe := Redis.TxPipeline()
+	pipe.Set(ctx, autosaveKey(member), value, utils.AutosaveTTL)
+	pipe.SAdd(ctx, autosaveDirtyKey, member)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// GetAutosave 返回该用户对该文章最新的自动保存，优先读取 Redis，其次读取数据库
+func GetAutosave(userID uint, articleID uint) (*Autosave, error) {
+	var data Autosave
+	value, err := Redis.Get(ctx, autosaveKey(autosaveMember(userID, articleID))).Bytes()
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(value, &data); err != nil {
+			return nil, err
+		}
+	case errors.Is(err, redis.Nil):
+		err := db.Where("user_id = ? AND article_id = ?", userID, articleID).First(&data).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errmsg.ErrAutosaveNotExist
+			}
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	if articleID > 0 {
+		var article Article
+		if err := db.Select("id", "version").First(&article, articleID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errmsg.ErrArticleNotExist
+			}
+			return nil, err
+		}
+		data.Outdated = data.BaseVersion != 0 && data.BaseVersion != article.Version
+	}
+	return &data, nil
+}
+
+// DiscardAutosave 丢弃该用户对该文章的自动保存，文章发布或保存后调用。
+// 同时记录丢弃时间，防止正在执行的写入任务把已丢弃的自动保存重新写入数据库
+func DiscardAutosave(userID uint, articleID uint) error {
+	member := autosaveMember(userID, articleID)
+	pipe := Redis.TxPipeline()
+	pipe.Set(ctx, autosaveDiscardKey(member), time.Now().UnixNano(), autosaveDiscardTTL)
+	pipe.Del(ctx, autosaveKey(member))
+	pipe.SRem(ctx, autosaveDirtyKey, member)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+	return db.Where("user_id = ? AND article_id = ?", userID, articleID).Delete(&Autosave{}).Error
+}
+
+// FlushAutosaves 将 Redis 中尚未写入数据库的自动保存写入数据库
+func FlushAutosaves() error {
+	members, err := Redis.SMembers(ctx, autosaveDirtyKey).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, member := range members {
+		// 先移出待写入集合，写入期间的新保存会重新加入集合，不会丢失
+		if err := Redis.SRem(ctx, autosaveDirtyKey, member).Err(); err != nil {
+			return err
+		}
+		value, err := Redis.Get(ctx, autosaveKey(member)).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue // 已过期或已丢弃
+		}
+		if err != nil {
+			return err
+		}
+
+		var data Autosave
+		if err := json.Unmarshal(value, &data); err != nil {
+			log.Printf("自动保存 %s 格式无效: %v", member, err)
+			continue
+		}
+		if err := db.Clauses(clause.OnConflict{UpdateAll: true}).Create(&data).Error; err != nil {
+			Redis.SAdd(ctx, autosaveDirtyKey, member)
+			return err
+		}
+		if err := removeDiscardedAutosave(member, &data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeDiscardedAutosave 检查刚写入数据库的自动保存是否已被丢弃：丢弃发生在读取 Redis 和写入数据库之间时，
+// 删除写入的旧数据。丢弃之后的新保存 (SavedAt 更晚) 不受影响
+func removeDiscardedAutosave(member string, data *Autosave) error {
+	discardedAt, err := Redis.Get(ctx, autosaveDiscardKey(member)).Int64()
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	at := time.Unix(0, discardedAt)
+	if data.SavedAt.After(at) {
+		return nil
+	}
+	return db.Where("user_id = ? AND article_id = ? AND saved_at <= ?", data.UserID, data.ArticleID, at).Delete(&Autosave{}).Error
+}
+
+// InitAutosave 启动后台任务，定期将自动保存写入数据库，需在 InitDb 和 InitRedis 之后调用
+func InitAutosave() {
+	go func() {
+		ticker := time.NewTicker(utils.AutosaveFlushInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := FlushAutosaves(); err != nil {
+				log.Printf("自动保存写入数据库失败: %v", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,194 @@
+package model
+
+import (
+	"errors"
+	"goblog/utils/errmsg"
+
+	"gorm.io/gorm"
+)
+
+// 批量操作文章的类型
+const (
+	BulkCategory   = "category"    // 修改分类
+	BulkAddTags    = "add_tags"    // 添加标签
+	BulkRemoveTags = "remove_tags" // 移除标签
+	BulkPublish    = "publish"     // 发布
+	BulkUnpublish  = "unpublish"   // 撤回为草稿
+	BulkTrash      = "trash"       // 移入回收站
+	BulkRestore    = "restore"     // 从回收站恢复
+)
+
+// BulkArticleOp 是一次批量操作的参数
+type BulkArticleOp struct {
+	Op     string
+	IDs    []uint
+	Cid    uint     // Op 为 category 时的目标分类
+	Tags   []string // Op 为 add_tags、remove_tags 时的标签
+	Atomic bool     // 为 true 时任意一篇失败则全部回滚
+}
+
+// BulkResult 是批量操作中单篇文章的结果
+type BulkResult struct {
+	ID      uint   `json:"id"`
+	OK      bool   `json:"ok"`
+	Status  int    `json:"status,omitempty"`  // 失败时的业务状态码
+	Message string `json:"message,omitempty"` // 失败原因
+}
+
+// BulkReport 是批量操作的汇总结果
+type BulkReport struct {
+	Op         string       `json:"op"`
+	Succeeded  int          `json:"succeeded"`
+	Failed     int          `json:"failed"`
+	RolledBack bool         `json:"rolledBack"` // Atomic 模式下因部分失败而全部回滚
+	Results    []BulkResult `json:"results"`
+}
+
+// errBulkRollback 用于在 Atomic 模式下回滚整个事务
+var errBulkRollback = errors.New("bulk operation rolled back")
+
+// BulkArticles 在同一个事务中对多篇文章执行同一操作，并记录审计日志。
+// 每篇文章在独立的保存点中执行，业务错误 (如文章不存在) 只影响该篇并记入结果，数据库错误则回滚全部
+func BulkArticles(userID uint, op *BulkArticleOp) (*BulkReport, error) {
+	report := &BulkReport{Op: op.Op, Results: make([]BulkResult, 0, len(op.IDs))}
+	succeeded := make([]uint, 0, len(op.IDs))
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		apply, err := prepareBulk(tx, op)
+		if err != nil {
+			return err
+		}
+
+		seen := make(map[uint]bool, len(op.IDs))
+		for _, id := range op.IDs {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			err := tx.Transaction(func(tx *gorm.DB) error {
+				return apply(tx, id)
+			})
+			var appErr *errmsg.AppError
+			switch {
+			case err == nil:
+				report.Results = append(report.Results, BulkResult{ID: id, OK: true})
+				succeeded = append(succeeded, id)
+			case errors.As(err, &appErr):
+				report.Results = append(report.Results, BulkResult{ID: id, Status: appErr.Status, Message: appErr.Message})
+				report.Failed++
+			default:
+				return err
+			}
+		}
+
+		if op.Atomic && report.Failed > 0 {
+			return errBulkRollback
+		}
+		if len(succeeded) == 0 {
+			return nil
+		}
+		return recordAudit(tx, &AuditLog{
+			UserID:     userID,
+			Action:     "article.bulk." + op.Op,
+			TargetType: "article",
+			TargetIDs:  succeeded,
+			Detail:     map[string]any{"cid": op.Cid, "tags": op.Tags, "results": report.Results},
+		})
+	})
+
+	if errors.Is(err, errBulkRollback) {
+		report.RolledBack = true
+		for i := range report.Results {
+			if report.Results[i].OK {
+				report.Results[i].OK = false
+				report.Results[i].Message = "其他文章操作失败，已回滚"
+			}
+		}
+		report.Failed = len(report.Results)
+		return report, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	report.Succeeded = len(succeeded)
+
+	for _, id := range succeeded {
+		if op.Op == BulkTrash {
+			searchEngine.remove(id)
+		} else {
+			searchEngine.update(id)
+		}
+	}
+	if len(succeeded) > 0 {
+		invalidateContentCache()
+	}
+	return report, nil
+}
+
+// prepareBulk 校验操作参数，返回对单篇文章执行操作的函数
+func prepareBulk(tx *gorm.DB, op *BulkArticleOp) (func(tx *gorm.DB, id uint) error, error) {
+	switch op.Op {
+	case BulkCategory:
+		if err := tx.Select("id").First(&Category{}, op.Cid).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errmsg.ErrCateNotExist
+			}
+			return nil, err
+		}
+		return func(tx *gorm.DB, id uint) error {
+			return bumpArticle(tx, id, map[string]any{"cid": op.Cid})
+		}, nil
+	case BulkAddTags:
+		tags, err := findOrCreateTags(tx, op.Tags)
+		if err != nil {
+			return nil, err
+		}
+		return func(tx *gorm.DB, id uint) error {
+			if err := bumpArticle(tx, id, nil); err != nil {
+				return err
+			}
+			return tx.Model(&Article{BaseModel: BaseModel{ID: id}}).Association("Tags").Append(tags)
+		}, nil
+	case BulkRemoveTags:
+		var tags []Tag
+		if err := tx.Where("name IN ?", op.Tags).Find(&tags).Error; err != nil {
+			return nil, err
+		}
+		return func(tx *gorm.DB, id uint) error {
+			if err := bumpArticle(tx, id, nil); err != nil {
+				return err
+			}
+			if len(tags) == 0 {
+				return nil
+			}
+			return tx.Model(&Article{BaseModel: BaseModel{ID: id}}).Association("Tags").Delete(tags)
+		}, nil
+	case BulkPublish, BulkUnpublish:
+		draft := op.Op == BulkUnpublish
+		return func(tx *gorm.DB, id uint) error {
+			return bumpArticle(tx, id, map[string]any{"draft": draft})
+		}, nil
+	case BulkTrash:
+		return deleteArticle, nil
+	case BulkRestore:
+		return restoreArticle, nil
+	}
+	return nil, errmsg.ErrBulkOp
+}
+
+// bumpArticle 更新文章并将版本号加一，使正在编辑该文章的用户能发现冲突
+func bumpArticle(tx *gorm.DB, id uint, updates map[string]any) error {
+	if updates == nil {
+		updates = map[string]any{}
+	}
+	updates["version"] = gorm.Expr("version + 1")
+	result := tx.Model(&Article{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errmsg.ErrArticleNotExist
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// contentVersionKey 保存内容版本号。订阅源等依赖文章内容的缓存都以版本号为键的一部分，
+// 文章变更时递增版本号即可让这些缓存全部失效，无需逐个删除
+const contentVersionKey = "cache:content:version"
+
+// contentVersion 返回当前的内容版本号
+func contentVersion() int64 {
+	if Redis == nil {
+		return 0
+	}
+	v, _ := Redis.Get(ctx, contentVersionKey).Int64()
+	return v
+}
+
+// invalidateContentCache 在文章新增、修改或删除后调用，使依赖文章内容的缓存失效
+func invalidateContentCache() {
+	if Redis == nil {
+		return
+	}
+	Redis.Incr(ctx, contentVersionKey)
+}
+
+// getCache 读取 JSON 缓存，不存在或 Redis 不可用时返回 false
+func getCache(key string, v any) bool {
+	if Redis == nil {
+		return false
+	}
+	data, err := Redis.Get(ctx, key).Bytes()
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+// setCache 写入 JSON 缓存，缓存失败不影响业务
+func setCache(key string, v any, ttl time.Duration) {
+	if Redis == nil {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	Redis.Set(ctx, key, data, ttl)
+}
+
+// Document 是渲染好的订阅源、站点地图等内容，附带用于条件请求的 ETag 和最后修改时间
+type Document struct {
+	Body         []byte    `json:"body"`
+	ContentType  string    `json:"contentType"`
+	ETag         string    `json:"etag"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+// newDocument 创建 Document，ETag 取内容的哈希
+func newDocument(body []byte, contentType string, lastModified time.Time) *Document {
+	sum := sha1.Sum(body)
+	return &Document{
+		Body:         body,
+		ContentType:  contentType,
+		ETag:         `"` + hex.EncodeToString(sum[:]) + `"`,
+		LastModified: lastModified,
+	}
+}
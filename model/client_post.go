@@ -0,0 +1,229 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"goblog/utils"
+	"goblog/utils/errmsg"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// clientTitleLength 是没有标题时从正文截取的标题长度 (字符数)
+const clientTitleLength = 30
+
+// ClientPost 是外部写作客户端 (如通过 MetaWeblog API 发布的桌面编辑器) 读写的文章。
+// 文章只有一个分类，客户端提交的第一个分类作为文章分类，其余分类作为标签保存；
+// 没有分类时 (如 Micropub 的 note) 使用配置的默认分类
+type ClientPost struct {
+	ID         uint
+	Title      string // 为空时截取正文第一行
+	Content    string // Markdown，可以包含 HTML
+	Desc       string
+	Slug       string
+	Categories []string
+	Tags       []string
+	Draft      bool
+	CreatedAt  time.Time // 为零值时使用当前时间
+	UpdatedAt  time.Time
+	AuthorID   uint
+	Link       string // 文章在前台的地址
+}
+
+// clientUser 将用户转换为访问者，用于权限判断
+func clientUser(u *User) Viewer {
+	return Viewer{UserID: u.ID, Role: u.Role}
+}
+
+// editableArticle 查询用户可以编辑的文章: 管理员可以编辑所有文章，其他用户只能编辑自己的文章
+func editableArticle(u *User, id uint) (*Article, error) {
+	var article Article
+	if err := db.Preload("Category").Preload("Tags").First(&article, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmsg.ErrArticleNotExist
+		}
+		return nil, err
+	}
+	if clientUser(u).isAdmin() {
+		return &article, nil
+	}
+	isAuthor, err := isArticleAuthor(id, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAuthor {
+		return nil, errmsg.ErrArticleForbidden
+	}
+	return &article, nil
+}
+
+// newClientPost 将文章转换为客户端读取的格式
+func newClientPost(a *Article, authorID uint) ClientPost {
+	p := ClientPost{
+		ID:        a.ID,
+		Title:     a.Title,
+		Content:   a.Content,
+		Desc:      a.Desc,
+		Slug:      a.Slug,
+		Tags:      TagNames(a.Tags),
+		Draft:     a.Draft,
+		CreatedAt: a.CreatedAt,
+		UpdatedAt: a.UpdatedAt,
+		AuthorID:  authorID,
+		Link:      fmt.Sprintf("%s/articles/%d", utils.SiteURL, a.ID),
+	}
+	if a.Category.Name != "" {
+		p.Categories = []string{a.Category.Name}
+	}
+	return p
+}
+
+// clientPostTitle 为没有标题的短文 (如 Micropub 的 note) 截取正文第一行作为标题，跳过图片
+func clientPostTitle(content string) string {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "#>-* "))
+		if line == "" || strings.HasPrefix(line, "![") || strings.HasPrefix(line, "<img") {
+			continue
+		}
+		return truncate(line, clientTitleLength)
+	}
+	return "无标题"
+}
+
+// apply 将客户端提交的内容写入文章，分类不存在时新建
+func (p *ClientPost) apply(a *Article) error {
+	var names []string
+	for _, name := range p.Categories {
+		if name = truncate(strings.TrimSpace(name), 20); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 && utils.ClientDefaultCategory != "" {
+		names = []string{truncate(utils.ClientDefaultCategory, 20)}
+	}
+	if len(names) == 0 {
+		return errmsg.ErrClientPostCategory
+	}
+	name := names[0]
+	var cate Category
+	err := db.Where("name = ?", name).First(&cate).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		cate = Category{Name: name}
+		err = CreateCategory(&cate)
+	}
+	if err != nil {
+		return err
+	}
+
+	title := strings.TrimSpace(p.Title)
+	if title == "" {
+		title = clientPostTitle(p.Content)
+	}
+	a.Title = truncate(title, 100)
+	a.Content = p.Content
+	a.Desc = truncate(p.Desc, 200)
+	a.Slug = p.Slug
+	a.Cid = cate.ID
+	a.Draft = p.Draft
+	a.Tags = make([]Tag, 0, len(p.Tags)+len(names)-1)
+	for _, tag := range append(p.Tags, names[1:]...) {
+		a.Tags = append(a.Tags, Tag{Name: truncate(strings.TrimSpace(tag), 20)})
+	}
+	return nil
+}
+
+// CreateClientPost 以用户的身份发布客户端提交的文章，返回文章 ID
+func CreateClientPost(u *User, p *ClientPost) (uint, error) {
+	article := &Article{}
+	if err := p.apply(article); err != nil {
+		return 0, err
+	}
+	if !p.CreatedAt.IsZero() {
+		article.CreatedAt = p.CreatedAt
+	}
+	if err := CreateArticle(article, u.ID); err != nil {
+		return 0, err
+	}
+	return article.ID, nil
+}
+
+// GetClientPost 查询用户可以编辑的文章
+func GetClientPost(u *User, id uint) (*ClientPost, error) {
+	article, err := editableArticle(u, id)
+	if err != nil {
+		return nil, err
+	}
+	authors, err := articleAuthors(id)
+	if err != nil {
+		return nil, err
+	}
+	p := newClientPost(article, authors[id])
+	return &p, nil
+}
+
+// GetRecentClientPosts 查询用户可以编辑的最新文章 (包括草稿)，管理员可以看到所有作者的文章
+func GetRecentClientPosts(u *User, limit int) ([]ClientPost, error) {
+	DB := db.Model(&Article{}).Preload("Category").Preload("Tags")
+	if !clientUser(u).isAdmin() {
+		DB = DB.Where("EXISTS (SELECT 1 FROM user_article WHERE user_article.article_id = article.id AND user_article.user_id = ?)", u.ID)
+	}
+	var articles []Article
+	if err := DB.Order("created_at DESC").Order("id DESC").Limit(limit).Find(&articles).Error; err != nil {
+		return nil, err
+	}
+
+	if len(articles) == 0 {
+		return []ClientPost{}, nil
+	}
+	ids := make([]uint, 0, len(articles))
+	for _, a := range articles {
+		ids = append(ids, a.ID)
+	}
+	authors, err := articleAuthors(ids...)
+	if err != nil {
+		return nil, err
+	}
+	posts := make([]ClientPost, 0, len(articles))
+	for i := range articles {
+		posts = append(posts, newClientPost(&articles[i], authors[articles[i].ID]))
+	}
+	return posts, nil
+}
+
+// EditClientPost 用客户端提交的内容更新文章，可见性、置顶等客户端不支持的设置保持不变
+func EditClientPost(u *User, id uint, p *ClientPost) error {
+	article, err := editableArticle(u, id)
+	if err != nil {
+		return err
+	}
+	if err := p.apply(article); err != nil {
+		return err
+	}
+	article.Password = "" // 沿用原访问密码
+	article.Version = 0   // 客户端不支持乐观锁，以最后一次提交为准
+	return EditArticle(id, article)
+}
+
+// DeleteClientPost 将用户可以编辑的文章移入回收站
+func DeleteClientPost(u *User, id uint) error {
+	if _, err := editableArticle(u, id); err != nil {
+		return err
+	}
+	return DeleteArticle(id)
+}
+
+// RestoreClientPost 从回收站恢复用户删除的文章，非管理员只能恢复自己的文章
+func RestoreClientPost(u *User, id uint) error {
+	if !clientUser(u).isAdmin() {
+		isAuthor, err := isArticleAuthor(id, u.ID)
+		if err != nil {
+			return err
+		}
+		if !isAuthor {
+			return errmsg.ErrArticleForbidden
+		}
+	}
+	return RestoreTrash(TrashArticles, id)
+}
@@ -47,10 +47,13 @@ func InitDb() {
 	}
 
 	// 迁移 schema
-	err = db.AutoMigrate(&User{}, &Article{}, &Category{}, &Comment{}, &Profile{}, &UserArticle{})
+	err = db.AutoMigrate(&User{}, &Article{}, &Category{}, &Comment{}, &Profile{}, &UserArticle{}, &Invitation{}, &Tag{}, &Reaction{}, &SlugRedirect{}, &SanitizeReport{}, &Series{}, &SeriesArticle{}, &Autosave{}, &AuditLog{}, &ImportSource{}, &AppPassword{})
 	if err != nil {
 		log.Fatalf("数据库迁移失败: %v", err)
 	}
+	if err := migrateSlugs(); err != nil {
+		log.Fatalf("生成 slug 失败: %v", err)
+	}
 
 	// 获取底层的 sql.DB 对象以配置连接池
 	sqlDB, err := db.DB()
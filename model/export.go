@@ -0,0 +1,402 @@
+package model
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"goblog/utils"
+	"goblog/utils/frontmatter"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// exportFormat 是导出压缩包的格式版本，结构变化时递增
+const exportFormat = 1
+
+// 导出时下载媒体文件的限制
+const (
+	exportMaxMedia     = 50 << 20 // 单个文件
+	exportMediaTimeout = time.Minute
+)
+
+// ExportOptions 是导出的公共参数
+type ExportOptions struct {
+	Media bool // 下载上传到对象存储的图片并写入导出结果，链接改为相对路径
+}
+
+// ExportManifest 是导出压缩包中的 manifest.json，记录导出时间和各类内容的数量
+type ExportManifest struct {
+	Format     int       `json:"format"`
+	Site       string    `json:"site"`
+	Title      string    `json:"title"`
+	ExportedAt time.Time `json:"exportedAt"`
+	Articles   int       `json:"articles"`
+	Categories int       `json:"categories"`
+	Tags       int       `json:"tags"`
+	Series     int       `json:"series"`
+	Comments   int       `json:"comments"`
+	Media      int       `json:"media"`
+	// 下载失败的媒体文件，文章中保留原地址
+	MissingMedia []string `json:"missingMedia,omitempty"`
+}
+
+// exportWriter 将导出的文件写入 ZIP 压缩包或目录，name 使用 / 分隔
+type exportWriter interface {
+	write(name string, data []byte) error
+}
+
+type zipExport struct {
+	zw *zip.Writer
+}
+
+func (z zipExport) write(name string, data []byte) error {
+	w, err := z.zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+type dirExport string
+
+func (d dirExport) write(name string, data []byte) error {
+	file := filepath.Join(string(d), filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0o644)
+}
+
+// writeJSON 将数据编码为缩进的 JSON 文件
+func writeJSON(out exportWriter, name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return out.write(name, append(data, '\n'))
+}
+
+// exportMedia 下载正文中引用的对象存储文件，同一文件只下载一次
+type exportMedia struct {
+	out     exportWriter
+	pattern *regexp.Regexp    // 匹配对象存储地址，未配置或未启用时为 nil
+	files   map[string]string // 原地址 -> 导出结果中的路径，下载失败时为空
+	saved   map[string]bool   // 已写入的路径，仅查询参数不同的地址对应同一个文件
+	missing []string
+	client  *http.Client
+}
+
+func newExportMedia(out exportWriter, enabled bool) *exportMedia {
+	m := &exportMedia{out: out, files: map[string]string{}, saved: map[string]bool{}, client: &http.Client{Timeout: exportMediaTimeout}}
+	if enabled && ImgUrl != "" {
+		m.pattern = regexp.MustCompile(regexp.QuoteMeta(ImgUrl) + `[^\s"'<>()\[\]]+`)
+	}
+	return m
+}
+
+// rewrite 下载 s 中引用的文件，并将地址替换为 prefix 加上文件在导出结果中的路径
+func (m *exportMedia) rewrite(s string, prefix string) string {
+	if m.pattern == nil || s == "" {
+		return s
+	}
+	return m.pattern.ReplaceAllStringFunc(s, func(u string) string {
+		if name := m.fetch(u); name != "" {
+			return prefix + name
+		}
+		return u
+	})
+}
+
+// fetch 下载一个文件并返回其路径，失败时记录并返回空字符串
+func (m *exportMedia) fetch(u string) string {
+	if name, ok := m.files[u]; ok {
+		return name
+	}
+	key := strings.TrimPrefix(u, ImgUrl)
+	if i := strings.IndexAny(key, "?#"); i >= 0 {
+		key = key[:i]
+	}
+	name := "media/" + strings.TrimPrefix(path.Clean("/"+key), "/")
+	if name == "media/" {
+		name = ""
+	}
+	if name != "" && !m.saved[name] {
+		if err := m.download(u, name); err != nil {
+			name = ""
+		}
+	}
+	if name == "" {
+		m.missing = append(m.missing, u)
+	} else {
+		m.saved[name] = true
+	}
+	m.files[u] = name
+	return name
+}
+
+func (m *exportMedia) download(u string, name string) error {
+	resp, err := m.client.Get(u)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", u, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, exportMaxMedia+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > exportMaxMedia {
+		return fmt.Errorf("%s 超过 %d MB", u, exportMaxMedia>>20)
+	}
+	return m.out.write(name, data)
+}
+
+// count 返回成功下载的文件数
+func (m *exportMedia) count() int {
+	return len(m.saved)
+}
+
+// 导出压缩包中分类、标签、系列和评论的 JSON 结构
+type (
+	exportCategory struct {
+		ID   uint   `json:"id"`
+		Name string `json:"name"`
+		Slug string `json:"slug"`
+	}
+	exportTag struct {
+		ID    uint   `json:"id"`
+		Name  string `json:"name"`
+		Count int64  `json:"count"`
+	}
+	exportSeriesItem struct {
+		ID       uint      `json:"id"`
+		Title    string    `json:"title"`
+		Desc     string    `json:"desc"`
+		Articles []string  `json:"articles"` // 按顺序排列的文章 slug
+		Created  time.Time `json:"createdAt"`
+	}
+	exportComment struct {
+		ID          uint      `json:"id"`
+		Article     string    `json:"article"` // 文章 slug
+		ArticleID   uint      `json:"articleId"`
+		ParentID    uint      `json:"parentId,omitempty"`
+		Commentator string    `json:"commentator"`
+		Content     string    `json:"content"`
+		CreatedAt   time.Time `json:"createdAt"`
+	}
+)
+
+// ExportArchive 将全站内容导出为 ZIP 压缩包并写入 w:
+// articles/ 下每篇文章一个带 YAML 元信息的 Markdown 文件 (可通过 import-markdown 重新导入)，
+// categories.json、tags.json、series.json、comments.json，manifest.json，以及启用 Media 时的 media/ 目录。
+// 回收站中的文章不导出，草稿和非公开文章会导出并在元信息中标注可见性。
+// 访问密码 (哈希) 不导出，受密码保护的文章重新导入后为私密文章，需重新设置密码
+func ExportArchive(w io.Writer, opts ExportOptions) (*ExportManifest, error) {
+	zw := zip.NewWriter(w)
+	out := zipExport{zw: zw}
+	manifest := &ExportManifest{
+		Format:     exportFormat,
+		Site:       utils.SiteURL,
+		Title:      utils.SiteTitle,
+		ExportedAt: time.Now(),
+	}
+	media := newExportMedia(out, opts.Media)
+
+	slugs, err := exportArticles(out, media, manifest)
+	if err != nil {
+		return nil, err
+	}
+	if err := exportTaxonomies(out, manifest); err != nil {
+		return nil, err
+	}
+	if err := exportSeries(out, slugs, manifest); err != nil {
+		return nil, err
+	}
+	if err := exportComments(out, slugs, manifest); err != nil {
+		return nil, err
+	}
+
+	manifest.Media = media.count()
+	manifest.MissingMedia = media.missing
+	if err := writeJSON(out, "manifest.json", manifest); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// exportArticles 分批导出文章，返回文章 ID 到 slug 的映射
+func exportArticles(out exportWriter, media *exportMedia, manifest *ExportManifest) (map[uint]string, error) {
+	authors, err := articleAuthors()
+	if err != nil {
+		return nil, err
+	}
+	var users []User
+	if err := db.Select("id", "username").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	usernames := make(map[uint]string, len(users))
+	for _, u := range users {
+		usernames[u.ID] = u.Username
+	}
+
+	slugs := map[uint]string{}
+	used := map[string]bool{}
+	var batch []Article
+	err = db.Preload("Category").Preload("Tags").FindInBatches(&batch, 100, func(tx *gorm.DB, _ int) error {
+		for i := range batch {
+			a := &batch[i]
+			// 旧数据的 slug 可能为空或重复，文件名需要唯一
+			name := a.Slug
+			switch {
+			case name == "":
+				name = fmt.Sprintf("article-%d", a.ID)
+			case used[name]:
+				name = fmt.Sprintf("%s-%d", name, a.ID)
+			}
+			used[name] = true
+			slugs[a.ID] = name
+
+			meta := frontmatter.Meta{
+				Title:       a.Title,
+				Slug:        a.Slug,
+				Date:        a.CreatedAt,
+				Description: a.Desc,
+				Image:       media.rewrite(a.Img, "../"),
+				Tags:        TagNames(a.Tags),
+				Draft:       a.Draft,
+			}
+			if a.Visibility != VisibilityPublic {
+				meta.Visibility = a.Visibility
+				meta.VisibleRole = a.VisibleRole
+			}
+			if a.Category.Name != "" {
+				meta.Categories = []string{a.Category.Name}
+			}
+			extra := map[string]any{
+				"id":       a.ID,
+				"updated":  a.UpdatedAt,
+				"author":   usernames[authors[a.ID]],
+				"pinned":   a.Pinned,
+				"featured": a.Featured,
+				"weight":   a.SortWeight,
+			}
+			data, err := frontmatter.Format(meta, extra, []byte(media.rewrite(a.Content, "../")))
+			if err != nil {
+				return err
+			}
+			if err := out.write("articles/"+name+".md", data); err != nil {
+				return err
+			}
+			manifest.Articles++
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	return slugs, nil
+}
+
+// exportTaxonomies 导出分类和标签
+func exportTaxonomies(out exportWriter, manifest *ExportManifest) error {
+	var cates []Category
+	if err := db.Order("id").Find(&cates).Error; err != nil {
+		return err
+	}
+	categories := make([]exportCategory, 0, len(cates))
+	for _, c := range cates {
+		categories = append(categories, exportCategory{ID: c.ID, Name: c.Name, Slug: c.Slug})
+	}
+	if err := writeJSON(out, "categories.json", categories); err != nil {
+		return err
+	}
+	manifest.Categories = len(categories)
+
+	var counts []TagCount
+	err := db.Model(&Tag{}).
+		Select("tag.id, tag.name, COUNT(article.id) AS count").
+		Joins("LEFT JOIN article_tag ON article_tag.tag_id = tag.id").
+		Joins("LEFT JOIN article ON article.id = article_tag.article_id AND article.deleted_at IS NULL").
+		Group("tag.id, tag.name").Order("tag.id").Scan(&counts).Error
+	if err != nil {
+		return err
+	}
+	tags := make([]exportTag, 0, len(counts))
+	for _, t := range counts {
+		tags = append(tags, exportTag{ID: t.ID, Name: t.Name, Count: t.Count})
+	}
+	manifest.Tags = len(tags)
+	return writeJSON(out, "tags.json", tags)
+}
+
+// exportSeries 导出系列及其文章顺序
+func exportSeries(out exportWriter, slugs map[uint]string, manifest *ExportManifest) error {
+	var list []Series
+	if err := db.Order("id").Find(&list).Error; err != nil {
+		return err
+	}
+	var parts []SeriesArticle
+	if err := db.Order("series_id, position").Find(&parts).Error; err != nil {
+		return err
+	}
+	articles := map[uint][]string{}
+	for _, p := range parts {
+		if slug, ok := slugs[p.ArticleID]; ok {
+			articles[p.SeriesID] = append(articles[p.SeriesID], slug)
+		}
+	}
+
+	series := make([]exportSeriesItem, 0, len(list))
+	for _, s := range list {
+		item := exportSeriesItem{ID: s.ID, Title: s.Title, Desc: s.Desc, Articles: articles[s.ID], Created: s.CreatedAt}
+		if item.Articles == nil {
+			item.Articles = []string{}
+		}
+		series = append(series, item)
+	}
+	manifest.Series = len(series)
+	return writeJSON(out, "series.json", series)
+}
+
+// exportComments 导出已导出文章下的评论
+func exportComments(out exportWriter, slugs map[uint]string, manifest *ExportManifest) error {
+	comments := []exportComment{}
+	var batch []Comment
+	err := db.FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+		for _, c := range batch {
+			slug, ok := slugs[c.ArticleID]
+			if !ok {
+				continue // 文章在回收站中
+			}
+			comments = append(comments, exportComment{
+				ID:          c.ID,
+				Article:     slug,
+				ArticleID:   c.ArticleID,
+				ParentID:    c.ParentID,
+				Commentator: c.Commentator,
+				Content:     c.Content,
+				CreatedAt:   c.CreatedAt,
+			})
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return err
+	}
+	manifest.Comments = len(comments)
+	return writeJSON(out, "comments.json", comments)
+}
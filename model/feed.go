@@ -0,0 +1,218 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"goblog/utils"
+	"goblog/utils/errmsg"
+	"goblog/utils/feed"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// feedCacheTpl 是渲染结果的缓存键: 内容版本号、订阅范围、格式
+const feedCacheTpl = "feed:v%d:%s:%s"
+
+const feedCacheTTL = time.Hour
+
+// 订阅范围
+const (
+	FeedScopeSite     = "site"
+	FeedScopeCategory = "category"
+	FeedScopeTag      = "tag"
+	FeedScopeAuthor   = "author"
+)
+
+// FeedScope 指定订阅源包含哪些文章: 全站、某个分类、某个标签或某位作者
+type FeedScope struct {
+	Kind string
+	ID   uint   // 分类ID或作者的用户ID
+	Tag  string // 标签名
+}
+
+func (s FeedScope) key() string {
+	switch s.Kind {
+	case FeedScopeTag:
+		return s.Kind + ":" + s.Tag
+	case FeedScopeCategory, FeedScopeAuthor:
+		return s.Kind + ":" + strconv.FormatUint(uint64(s.ID), 10)
+	default:
+		return FeedScopeSite
+	}
+}
+
+// path 返回订阅源相对于 API 根地址的路径
+func (s FeedScope) path(format string) string {
+	switch s.Kind {
+	case FeedScopeCategory:
+		return fmt.Sprintf("/feeds/categories/%d/%s", s.ID, format)
+	case FeedScopeTag:
+		return fmt.Sprintf("/feeds/tags/%s/%s", url.PathEscape(s.Tag), format)
+	case FeedScopeAuthor:
+		return fmt.Sprintf("/feeds/authors/%d/%s", s.ID, format)
+	default:
+		return "/feeds/" + format
+	}
+}
+
+// GetFeed 返回指定范围和格式 (rss/atom/json) 的订阅源，结果缓存在 Redis 中，文章变更后失效
+func GetFeed(scope FeedScope, format string) (*Document, error) {
+	contentType, ok := feed.ContentTypes[format]
+	if !ok {
+		return nil, errmsg.ErrFeedFormat
+	}
+
+	cacheKey := fmt.Sprintf(feedCacheTpl, contentVersion(), scope.key(), format)
+	var cached Document
+	if getCache(cacheKey, &cached) {
+		return &cached, nil
+	}
+
+	f, err := buildFeed(scope, format)
+	if err != nil {
+		return nil, err
+	}
+	body, err := feed.Render(f, format)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := newDocument(body, contentType, f.Updated)
+	setCache(cacheKey, doc, feedCacheTTL)
+	return doc, nil
+}
+
+// buildFeed 查询订阅范围内的最新文章并转换为订阅源
+func buildFeed(scope FeedScope, format string) (*feed.Feed, error) {
+	f := &feed.Feed{
+		Title:       utils.SiteTitle,
+		Link:        utils.SiteURL + "/",
+		FeedURL:     utils.SiteAPI + scope.path(format),
+		Description: utils.SiteDesc,
+	}
+
+	DB := filterPublic(db.Model(&Article{}))
+	switch scope.Kind {
+	case FeedScopeCategory:
+		var cate Category
+		if err := db.First(&cate, scope.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errmsg.ErrCateNotExist
+			}
+			return nil, err
+		}
+		f.Title += " - " + cate.Name
+		f.Link = fmt.Sprintf("%s/categories/%d", utils.SiteURL, cate.ID)
+		DB = DB.Where("cid = ?", cate.ID)
+	case FeedScopeTag:
+		var tag Tag
+		if err := db.Where("name = ?", scope.Tag).First(&tag).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errmsg.ErrTagNotExist
+			}
+			return nil, err
+		}
+		f.Title += " - #" + tag.Name
+		DB = DB.Where("EXISTS (SELECT 1 FROM article_tag WHERE article_tag.article_id = article.id AND article_tag.tag_id = ?)", tag.ID)
+	case FeedScopeAuthor:
+		names, err := authorNames([]uint{scope.ID})
+		if err != nil {
+			return nil, err
+		}
+		name, ok := names[scope.ID]
+		if !ok {
+			return nil, errmsg.ErrUserNotExist
+		}
+		f.Title += " - " + name
+		DB = DB.Where("EXISTS (SELECT 1 FROM user_article WHERE user_article.article_id = article.id AND user_article.user_id = ?)", scope.ID)
+	}
+
+	var articles []Article
+	err := DB.Preload("Category").Preload("Tags").Order("created_at DESC").Limit(utils.FeedLimit).Find(&articles).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(articles))
+	for _, a := range articles {
+		ids = append(ids, a.ID)
+	}
+	authors, err := articleAuthors(ids...)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]uint, 0, len(authors))
+	for _, uid := range authors {
+		userIDs = append(userIDs, uid)
+	}
+	names, err := authorNames(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range articles {
+		link := fmt.Sprintf("%s/articles/%d", utils.SiteURL, a.ID)
+		item := feed.Item{
+			ID:         link,
+			Title:      a.Title,
+			Link:       link,
+			Summary:    articleSummary(&a),
+			Author:     names[authors[a.ID]],
+			Categories: append([]string{a.Category.Name}, TagNames(a.Tags)...),
+			Published:  a.CreatedAt,
+			Updated:    a.UpdatedAt,
+		}
+		if utils.FeedContent == utils.FeedContentFull {
+			rendered, err := renderMarkdown(a.Content)
+			if err != nil {
+				return nil, err
+			}
+			item.Content = rendered.HTML
+		}
+		f.Items = append(f.Items, item)
+		if a.UpdatedAt.After(f.Updated) {
+			f.Updated = a.UpdatedAt
+		}
+	}
+	if f.Updated.IsZero() {
+		f.Updated = time.Unix(0, 0)
+	}
+	return f, nil
+}
+
+// authorNames 返回用户的显示名称，优先使用个人信息中的昵称
+func authorNames(userIDs []uint) (map[uint]string, error) {
+	names := make(map[uint]string, len(userIDs))
+	if len(userIDs) == 0 {
+		return names, nil
+	}
+	var users []User
+	if err := db.Preload("Profile").Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		names[u.ID] = u.Username
+		if u.Profile.Name != "" {
+			names[u.ID] = u.Profile.Name
+		}
+	}
+	return names, nil
+}
+
+const summaryLength = 200 // 没有填写摘要时截取的正文长度 (字符数)
+
+// articleSummary 返回文章摘要，未填写时截取正文开头
+func articleSummary(a *Article) string {
+	if a.Desc != "" {
+		return a.Desc
+	}
+	runes := []rune(strings.TrimSpace(a.Content))
+	if len(runes) <= summaryLength {
+		return string(runes)
+	}
+	return string(runes[:summaryLength]) + "…"
+}
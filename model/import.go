@@ -0,0 +1,277 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"goblog/utils"
+	"goblog/utils/errmsg"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// 导入时对单篇文章的处理结果
+const (
+	ImportCreate = "create" // 新建 (试运行时表示将会新建)
+	ImportSkip   = "skip"   // 已存在，跳过
+	ImportFail   = "error"  // 无法导入
+)
+
+// 导入来源中内容的类型
+const (
+	SourcePost    = "post"
+	SourceComment = "comment"
+	SourceUser    = "user"
+)
+
+// ImportSource 记录导入的内容与来源中原始 ID 的对应关系，重复导入时据此跳过已导入的内容
+type ImportSource struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	CreatedAt  time.Time `json:"createdAt"`
+	Source     string    `gorm:"type:varchar(191);not null;uniqueIndex:idx_import_source,priority:1" json:"source"` // 来源站点
+	Kind       string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_import_source,priority:2;index:idx_import_local,priority:1" json:"kind"`
+	ExternalID string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_import_source,priority:3" json:"externalId"` // 来源中的 ID
+	LocalID    uint      `gorm:"not null;index:idx_import_local,priority:2" json:"localId"`
+}
+
+// ImportOptions 是导入的公共参数
+type ImportOptions struct {
+	AuthorID        uint   // 导入文章的作者
+	DefaultCategory string // 没有分类的文章归入该分类
+	DryRun          bool   // 只生成报告，不写入数据库，不上传图片
+}
+
+// ImportImage 是文章中引用的一张本地图片
+type ImportImage struct {
+	Ref     string `json:"ref"`               // 文章中的引用路径
+	File    string `json:"file,omitempty"`    // 压缩包中对应的文件
+	URL     string `json:"url,omitempty"`     // 上传后的地址
+	Missing bool   `json:"missing,omitempty"` // 压缩包中找不到该图片，保留原路径
+}
+
+// ImportItem 是单篇文章的导入结果
+type ImportItem struct {
+	Source     string        `json:"source"` // 来源文件或条目
+	Title      string        `json:"title"`
+	Slug       string        `json:"slug"`
+	Category   string        `json:"category"`
+	Tags       []string      `json:"tags"`
+	Date       time.Time     `json:"date"`
+	Draft      bool          `json:"draft"`
+	Visibility string        `json:"visibility,omitempty"` // 非公开文章的可见性
+	Action     string        `json:"action"`
+	Message    string        `json:"message,omitempty"` // 跳过或失败的原因
+	Warnings   []string      `json:"warnings,omitempty"`
+	Images     []ImportImage `json:"images,omitempty"`
+	Comments   int           `json:"comments,omitempty"` // 新导入 (试运行时为将要导入) 的评论数
+	ArticleID  uint          `json:"articleId,omitempty"`
+}
+
+// ImportReport 是一次导入的汇总报告
+type ImportReport struct {
+	DryRun        bool         `json:"dryRun"`
+	Total         int          `json:"total"`
+	Created       int          `json:"created"`
+	Skipped       int          `json:"skipped"`
+	Failed        int          `json:"failed"`
+	NewCategories []string     `json:"newCategories"`        // 需要新建 (或已新建) 的分类
+	NewAuthors    []string     `json:"newAuthors,omitempty"` // 需要新建 (或已新建) 的作者
+	Warnings      []string     `json:"warnings,omitempty"`   // 与单篇文章无关的提示，如作者用户名被占用
+	Items         []ImportItem `json:"items"`
+}
+
+// add 记录单篇文章的结果并更新计数
+func (r *ImportReport) add(item ImportItem) {
+	r.Total++
+	switch item.Action {
+	case ImportCreate:
+		r.Created++
+	case ImportSkip:
+		r.Skipped++
+	default:
+		r.Failed++
+	}
+	r.Items = append(r.Items, item)
+}
+
+// importer 保存一次导入过程中的分类缓存
+type importer struct {
+	opts       ImportOptions
+	report     *ImportReport
+	categories map[string]uint // 分类名 -> ID，试运行时新分类的 ID 为 0
+	slugs      map[string]bool // 本次导入中已使用的 slug
+	rename     bool            // slug 被占用时追加后缀而不是跳过，用于以导入记录去重的来源 (WordPress)
+}
+
+// newImporter 校验作者并加载已有分类
+func newImporter(opts ImportOptions) (*importer, error) {
+	if err := db.Select("id").First(&User{}, opts.AuthorID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmsg.ErrUserNotExist
+		}
+		return nil, err
+	}
+
+	var cates []Category
+	if err := db.Select("id", "name").Find(&cates).Error; err != nil {
+		return nil, err
+	}
+	imp := &importer{
+		opts:       opts,
+		report:     &ImportReport{DryRun: opts.DryRun, NewCategories: []string{}, Items: []ImportItem{}},
+		categories: make(map[string]uint, len(cates)),
+		slugs:      map[string]bool{},
+	}
+	for _, cate := range cates {
+		imp.categories[cate.Name] = cate.ID
+	}
+	return imp, nil
+}
+
+// category 返回分类 ID，分类不存在时新建 (试运行时只记录)
+func (imp *importer) category(name string) (uint, error) {
+	if id, ok := imp.categories[name]; ok {
+		return id, nil
+	}
+	imp.report.NewCategories = append(imp.report.NewCategories, name)
+	if imp.opts.DryRun {
+		imp.categories[name] = 0
+		return 0, nil
+	}
+
+	cate := &Category{Name: name}
+	if err := CreateCategory(cate); err != nil {
+		return 0, err
+	}
+	imp.categories[name] = cate.ID
+	return cate.ID, nil
+}
+
+// claimSlug 判断 slug 是否可用：已被数据库中的文章或本次导入中的其他文章占用时返回 false
+func (imp *importer) claimSlug(slug string) (bool, error) {
+	if imp.slugs[slug] {
+		return false, nil
+	}
+	taken, err := slugTaken(db, SlugTargetArticle, slug, 0)
+	if err != nil || taken {
+		return false, err
+	}
+	imp.slugs[slug] = true
+	return true, nil
+}
+
+// claimUniqueSlug 与 uniqueSlug 一样在 slug 被占用时依次追加 -2、-3 等后缀，
+// 同时避开本次导入中其他文章已使用的 slug (试运行时这些文章尚未写入数据库)
+func (imp *importer) claimUniqueSlug(base string) (string, error) {
+	slug := base
+	for i := 2; ; i++ {
+		available, err := imp.claimSlug(slug)
+		if err != nil || available {
+			return slug, err
+		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// check 检查 slug 并确定分类，不能导入时设置 item 的结果并返回 false。
+// slug 已被占用时跳过该文章，设置了 rename 时改用带后缀的 slug。
+// 试运行时通过检查即视为将会新建
+func (imp *importer) check(item *ImportItem) (uint, bool) {
+	item.Action = ImportFail
+	slug := utils.Slugify(item.Slug)
+	if slug == "" {
+		slug = utils.Slugify(item.Title)
+	}
+	if slug == "" {
+		item.Message = "无法生成 slug"
+		return 0, false
+	}
+
+	if imp.rename {
+		renamed, err := imp.claimUniqueSlug(slug)
+		if err != nil {
+			item.Message = err.Error()
+			return 0, false
+		}
+		if renamed != slug {
+			item.Warnings = append(item.Warnings, fmt.Sprintf("slug %s 已被占用，改为 %s", slug, renamed))
+		}
+		slug = renamed
+	} else {
+		available, err := imp.claimSlug(slug)
+		if err != nil {
+			item.Message = err.Error()
+			return 0, false
+		}
+		if !available {
+			item.Action = ImportSkip
+			item.Message = "已存在相同 slug 的文章"
+			return 0, false
+		}
+	}
+	item.Slug = slug
+
+	if item.Category == "" {
+		item.Category = imp.opts.DefaultCategory
+	}
+	if item.Category == "" {
+		item.Message = "文章没有分类，且未指定默认分类"
+		return 0, false
+	}
+	cid, err := imp.category(item.Category)
+	if err != nil {
+		item.Message = err.Error()
+		return 0, false
+	}
+
+	if imp.opts.DryRun {
+		item.Action = ImportCreate
+	}
+	return cid, true
+}
+
+// create 创建通过检查的文章。article 中的正文、摘要、封面和可见性由调用方填写，其余字段取自 item；
+// after 与文章在同一事务中执行，用于记录导入来源
+func (imp *importer) create(item *ImportItem, article *Article, cid uint, authorID uint, after func(tx *gorm.DB) error) {
+	article.Title = item.Title
+	article.Slug = item.Slug
+	article.Cid = cid
+	article.Draft = item.Draft
+	article.CreatedAt = item.Date
+	article.UpdatedAt = item.Date
+	article.Tags = make([]Tag, 0, len(item.Tags))
+	for _, name := range item.Tags {
+		article.Tags = append(article.Tags, Tag{Name: name})
+	}
+	if err := createArticle(article, authorID, after); err != nil {
+		item.Action = ImportFail
+		item.Message = err.Error()
+		return
+	}
+	item.ArticleID = article.ID
+	item.Action = ImportCreate
+}
+
+// findSource 查找来源中的内容已导入为哪条记录，未导入时返回 0
+func findSource(source string, kind string, externalID string) (uint, error) {
+	var s ImportSource
+	err := db.Where("source = ? AND kind = ? AND external_id = ?", source, kind, externalID).Take(&s).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	return s.LocalID, err
+}
+
+// recordSource 记录来源中的内容已导入为 localID
+func recordSource(tx *gorm.DB, source string, kind string, externalID string, localID uint) error {
+	return tx.Create(&ImportSource{Source: source, Kind: kind, ExternalID: externalID, LocalID: localID}).Error
+}
+
+// truncate 按字符数截断字符串，用于适配数据库字段长度
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
@@ -0,0 +1,301 @@
+package model
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"goblog/utils/errmsg"
+	"goblog/utils/frontmatter"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 导入压缩包的大小限制
+const (
+	ImportMaxSize   = 100 << 20 // 压缩包
+	importMaxDoc    = 10 << 20  // 单个 Markdown 文件
+	importMaxImage  = 20 << 20  // 单张图片
+	importMaxImages = 2000      // 单次导入上传的图片总数
+)
+
+var (
+	// jekyllName 匹配 Jekyll 的文件名格式 2006-01-02-slug.md
+	jekyllName = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})-(.+)$`)
+	// mdImage 匹配 Markdown 图片 ![alt](path "title")
+	mdImage = regexp.MustCompile(`!\[[^\]]*\]\(\s*<?([^)\s>]+)>?(?:\s+["'][^"']*["'])?\s*\)`)
+	// htmlImage 匹配 HTML 图片 <img src="path">
+	htmlImage = regexp.MustCompile(`<img\b[^>]*?\bsrc\s*=\s*["']([^"']+)["']`)
+	// mdHeading 匹配一级标题，元信息中没有标题时使用
+	mdHeading = regexp.MustCompile(`(?m)^#\s+(.+?)\s*#*\s*$`)
+)
+
+// markdownImport 是一次 Markdown 压缩包导入
+type markdownImport struct {
+	*importer
+	files    map[string]*zip.File // 压缩包中的文件，键为规范化后的路径
+	uploaded map[string]string    // 已上传的图片，压缩包路径 -> 地址
+}
+
+// ImportMarkdownZip 从 Hexo、Hugo、Jekyll 等静态博客导出的 Markdown 压缩包导入文章。
+// 同 slug 的文章已存在时跳过，因此可以重复导入；试运行时只生成报告
+func ImportMarkdownZip(zr *zip.Reader, opts ImportOptions) (*ImportReport, error) {
+	imp, err := newImporter(opts)
+	if err != nil {
+		return nil, err
+	}
+	m := &markdownImport{importer: imp, files: map[string]*zip.File{}, uploaded: map[string]string{}}
+
+	var docs []string
+	for _, f := range zr.File {
+		name := path.Clean(strings.ReplaceAll(f.Name, "\\", "/"))
+		if f.FileInfo().IsDir() || strings.HasPrefix(name, "__MACOSX/") || strings.HasPrefix(path.Base(name), ".") {
+			continue
+		}
+		m.files[name] = f
+		switch strings.ToLower(path.Ext(name)) {
+		case ".md", ".markdown":
+			docs = append(docs, name)
+		}
+	}
+	if len(docs) == 0 {
+		return nil, errmsg.ErrImportEmpty
+	}
+	sort.Strings(docs)
+
+	for _, name := range docs {
+		item := m.importDoc(name)
+		imp.report.add(item)
+	}
+	return imp.report, nil
+}
+
+// importDoc 导入一个 Markdown 文件
+func (m *markdownImport) importDoc(name string) ImportItem {
+	item := ImportItem{Source: name, Action: ImportFail, Tags: []string{}}
+	src, err := readZipFile(m.files[name], importMaxDoc)
+	if err != nil {
+		item.Message = err.Error()
+		return item
+	}
+	meta, body, err := frontmatter.Parse(src)
+	if err != nil {
+		item.Message = err.Error()
+		return item
+	}
+	content := string(body)
+
+	// 元信息缺失时从文件名和正文推断：Jekyll 的文件名包含日期和 slug，Hugo 的页面包以目录名作为 slug
+	base := strings.TrimSuffix(path.Base(name), path.Ext(name))
+	if base == "index" || base == "_index" {
+		base = path.Base(path.Dir(name))
+	}
+	if match := jekyllName.FindStringSubmatch(base); match != nil {
+		base = match[2]
+		if meta.Date.IsZero() {
+			meta.Date, _ = time.ParseInLocation("2006-01-02", match[1], time.Local)
+		}
+	}
+	if meta.Title == "" {
+		if match := mdHeading.FindStringSubmatch(content); match != nil {
+			meta.Title = match[1]
+		} else {
+			meta.Title = base
+		}
+	}
+	if meta.Slug == "" {
+		meta.Slug = base
+	}
+	if meta.Date.IsZero() {
+		meta.Date = m.files[name].Modified.Local()
+		if meta.Date.Year() <= 1980 { // 压缩包没有记录修改时间 (DOS 时间的起点)
+			meta.Date = time.Now()
+		}
+		item.Warnings = append(item.Warnings, "缺少日期，使用文件修改时间")
+	}
+	if strings.HasPrefix(name, "_drafts/") || strings.Contains(name, "/_drafts/") {
+		meta.Draft = true
+	}
+
+	item.Title = truncate(meta.Title, 100)
+	item.Slug = meta.Slug
+	item.Date = meta.Date
+	item.Draft = meta.Draft
+	visibility, role := importVisibility(&item, meta)
+	// 文章只有一个分类，其余分类作为标签保留
+	if len(meta.Categories) > 0 {
+		item.Category = truncate(meta.Categories[0], 20)
+		meta.Tags = append(meta.Tags, meta.Categories[1:]...)
+	}
+	seen := map[string]bool{}
+	for _, tag := range meta.Tags {
+		tag = truncate(tag, 20)
+		if !seen[tag] {
+			seen[tag] = true
+			item.Tags = append(item.Tags, tag)
+		}
+	}
+
+	cid, ok := m.check(&item)
+	if !ok {
+		return item
+	}
+
+	content = m.rewriteImages(&item, name, content)
+	img := meta.Image
+	if img != "" {
+		img = m.rewriteImage(&item, name, img)
+	}
+	if m.opts.DryRun {
+		return item
+	}
+
+	article := &Article{Desc: truncate(meta.Description, 200), Content: content, Img: img, Visibility: visibility, VisibleRole: role}
+	m.create(&item, article, cid, m.opts.AuthorID, nil)
+	return item
+}
+
+// importVisibility 返回元信息中的可见性 (本站导出的文章)。访问密码不随压缩包导出，
+// 受密码保护的文章和无法识别的可见性按私密文章导入，避免非公开内容导入后被公开
+func importVisibility(item *ImportItem, meta frontmatter.Meta) (string, int) {
+	switch meta.Visibility {
+	case "", VisibilityPublic:
+		return VisibilityPublic, 0
+	case VisibilityUnlisted, VisibilityPrivate:
+		item.Visibility = meta.Visibility
+		return meta.Visibility, 0
+	case VisibilityRole:
+		item.Visibility = meta.Visibility
+		return meta.Visibility, meta.VisibleRole
+	case VisibilityPassword:
+		item.Warnings = append(item.Warnings, "原文章受密码保护，密码未导出，已导入为私密文章，请重新设置密码")
+	default:
+		item.Warnings = append(item.Warnings, fmt.Sprintf("无法识别的可见性 %q，已导入为私密文章", meta.Visibility))
+	}
+	item.Visibility = VisibilityPrivate
+	return VisibilityPrivate, 0
+}
+
+// rewriteImages 将正文中引用的本地图片上传，并替换为上传后的地址
+func (m *markdownImport) rewriteImages(item *ImportItem, doc string, content string) string {
+	for _, re := range []*regexp.Regexp{mdImage, htmlImage} {
+		content = re.ReplaceAllStringFunc(content, func(match string) string {
+			ref := re.FindStringSubmatch(match)[1]
+			if replaced := m.rewriteImage(item, doc, ref); replaced != ref {
+				return strings.Replace(match, ref, replaced, 1)
+			}
+			return match
+		})
+	}
+	return content
+}
+
+// rewriteImage 上传一张本地图片并返回新地址，远程图片和无法处理的图片返回原路径。试运行时只记录
+func (m *markdownImport) rewriteImage(item *ImportItem, doc string, ref string) string {
+	if ref == "" || strings.HasPrefix(ref, "//") || strings.HasPrefix(ref, "#") || strings.Contains(ref, ":") {
+		return ref // 远程地址、data URI 等
+	}
+
+	image := ImportImage{Ref: ref}
+	f := m.findImage(doc, ref)
+	if f == nil {
+		image.Missing = true
+		item.Images = append(item.Images, image)
+		item.Warnings = append(item.Warnings, fmt.Sprintf("压缩包中找不到图片 %s", ref))
+		return ref
+	}
+	image.File = f.Name
+	if m.opts.DryRun {
+		item.Images = append(item.Images, image)
+		return ref
+	}
+
+	u, ok := m.uploaded[f.Name]
+	if !ok {
+		var err error
+		if u, err = m.upload(f); err != nil {
+			image.Missing = true
+			item.Images = append(item.Images, image)
+			item.Warnings = append(item.Warnings, fmt.Sprintf("上传图片 %s 失败: %v", ref, err))
+			return ref
+		}
+		m.uploaded[f.Name] = u
+	}
+	image.URL = u
+	item.Images = append(item.Images, image)
+	return u
+}
+
+// findImage 在压缩包中查找图片。相对路径相对于文章所在目录 (以及 Hexo 的同名资源目录)；
+// 以 / 开头的路径在 Hugo 的 static、Hexo 的 source 目录下查找。都找不到时按路径后缀匹配
+func (m *markdownImport) findImage(doc string, ref string) *zip.File {
+	if i := strings.IndexAny(ref, "?#"); i >= 0 {
+		ref = ref[:i]
+	}
+	if unescaped, err := url.PathUnescape(ref); err == nil {
+		ref = unescaped
+	}
+
+	dir := path.Dir(doc)
+	var candidates []string
+	if strings.HasPrefix(ref, "/") {
+		candidates = []string{"static" + ref, "source" + ref, ref[1:]}
+	} else {
+		asset := strings.TrimSuffix(doc, path.Ext(doc))
+		candidates = []string{path.Join(dir, ref), path.Join(asset, ref), ref}
+	}
+	for _, c := range candidates {
+		if f, ok := m.files[path.Clean(c)]; ok {
+			return f
+		}
+	}
+
+	suffix := "/" + strings.TrimLeft(path.Clean("/"+ref), "/")
+	var found *zip.File
+	for name, f := range m.files {
+		if strings.HasSuffix(name, suffix) {
+			if found != nil {
+				return nil // 有多个同名文件，无法确定
+			}
+			found = f
+		}
+	}
+	return found
+}
+
+// upload 将压缩包中的图片上传到对象存储
+func (m *markdownImport) upload(f *zip.File) (string, error) {
+	if len(m.uploaded) >= importMaxImages {
+		return "", fmt.Errorf("单次导入最多上传 %d 张图片", importMaxImages)
+	}
+	data, err := readZipFile(f, importMaxImage)
+	if err != nil {
+		return "", err
+	}
+	return UploadFile(bytes.NewReader(data), int64(len(data)))
+}
+
+// readZipFile 读取压缩包中的文件，超过 limit 时返回错误
+func readZipFile(f *zip.File, limit int64) ([]byte, error) {
+	if f.UncompressedSize64 > uint64(limit) {
+		return nil, fmt.Errorf("%s 超过 %d MB", f.Name, limit>>20)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%s 超过 %d MB", f.Name, limit>>20)
+	}
+	return data, nil
+}
@@ -0,0 +1,461 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"goblog/utils/errmsg"
+	"goblog/utils/htmlmd"
+	"html"
+	"io"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// wxrDateLayout 是 WXR 中 post_date、comment_date 的格式 (站点本地时间)
+const wxrDateLayout = "2006-01-02 15:04:05"
+
+var (
+	// wxrPre 匹配代码块，自动分段时跳过
+	wxrPre = regexp.MustCompile(`(?is)<pre[\s>].*?</pre>`)
+	// wxrParagraph 匹配段落之间的空行
+	wxrParagraph = regexp.MustCompile(`\n[ \t]*\n`)
+	// wxrBlockEnd、wxrBlockStart 匹配以块级标签结尾或开头的行，这些行之间的换行不转换为 <br>
+	wxrBlockEnd   = regexp.MustCompile(`(?i)</?(?:` + wxrBlockTags + `)\b[^>]*>\s*$`)
+	wxrBlockStart = regexp.MustCompile(`(?i)^\s*</?(?:` + wxrBlockTags + `)\b`)
+)
+
+// wxrBlockTags 是 wpautop 视为块级元素的常见标签
+const wxrBlockTags = `p|div|ul|ol|li|h[1-6]|blockquote|pre|table|thead|tbody|tr|td|th|figure|figcaption|hr|iframe`
+
+// wxrDoc 是 WordPress 导出的 WXR (WordPress eXtended RSS) 文件。
+// 字段只按本地名匹配，兼容 WXR 1.0 ~ 1.2 的不同命名空间
+type wxrDoc struct {
+	Channel struct {
+		Title       string      `xml:"title"`
+		Link        string      `xml:"link"`
+		BaseSiteURL string      `xml:"base_site_url"`
+		Authors     []wxrAuthor `xml:"author"`
+		Items       []wxrItem   `xml:"item"`
+	} `xml:"channel"`
+}
+
+type wxrAuthor struct {
+	Login       string `xml:"author_login"`
+	Email       string `xml:"author_email"`
+	DisplayName string `xml:"author_display_name"`
+}
+
+type wxrItem struct {
+	Title      string        `xml:"title"`
+	PubDate    string        `xml:"pubDate"`
+	Creator    string        `xml:"creator"`
+	Encoded    []wxrEncoded  `xml:"encoded"` // content:encoded 和 excerpt:encoded
+	PostID     string        `xml:"post_id"`
+	PostDate   string        `xml:"post_date"`
+	PostName   string        `xml:"post_name"`
+	Status     string        `xml:"status"`
+	PostType   string        `xml:"post_type"`
+	Password   string        `xml:"post_password"`
+	Sticky     string        `xml:"is_sticky"`
+	Categories []wxrCategory `xml:"category"`
+	Comments   []wxrComment  `xml:"comment"`
+}
+
+type wxrEncoded struct {
+	XMLName xml.Name
+	Text    string `xml:",chardata"`
+}
+
+type wxrCategory struct {
+	Domain string `xml:"domain,attr"` // category 或 post_tag
+	Name   string `xml:",chardata"`
+}
+
+type wxrComment struct {
+	ID       string `xml:"comment_id"`
+	Author   string `xml:"comment_author"`
+	Date     string `xml:"comment_date"`
+	Content  string `xml:"comment_content"`
+	Approved string `xml:"comment_approved"`
+	Type     string `xml:"comment_type"`
+	Parent   string `xml:"comment_parent"`
+}
+
+// encoded 返回指定命名空间 (content 或 excerpt) 的正文
+func (it *wxrItem) encoded(kind string) string {
+	for _, e := range it.Encoded {
+		if strings.Contains(e.XMLName.Space, "/"+kind) {
+			return e.Text
+		}
+	}
+	return ""
+}
+
+// wxrImport 是一次 WordPress 导入
+type wxrImport struct {
+	*importer
+	source  string          // 来源站点，用于识别已导入的内容
+	authors map[string]uint // 登录名 -> 用户 ID
+}
+
+// ImportWXR 从 WordPress 导出的 WXR 文件导入文章、分类、标签、评论 (保留回复关系) 和作者。
+// 已导入的文章和评论记录在 ImportSource 中，重复导入时跳过，只补充新的评论；
+// slug 与已有文章相同的文章改用带后缀的 slug 导入。试运行时只生成报告
+func ImportWXR(r io.Reader, opts ImportOptions) (*ImportReport, error) {
+	var doc wxrDoc
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, errmsg.ErrImportFile.WithMsg("WXR 文件格式错误: %v", err)
+	}
+
+	source := doc.Channel.BaseSiteURL
+	if source == "" {
+		source = doc.Channel.Link
+	}
+	source = truncate(strings.TrimRight(strings.ToLower(strings.TrimSpace(source)), "/"), 191)
+	if source == "" {
+		return nil, errmsg.ErrImportFile.WithMsg("WXR 文件缺少站点地址")
+	}
+
+	imp, err := newImporter(opts)
+	if err != nil {
+		return nil, err
+	}
+	imp.rename = true // 以导入记录去重，slug 相同的文章是不同的文章
+	w := &wxrImport{importer: imp, source: source, authors: map[string]uint{}}
+	for _, a := range doc.Channel.Authors {
+		if err := w.author(a); err != nil {
+			return nil, err
+		}
+	}
+
+	posts := 0
+	for i := range doc.Channel.Items {
+		it := &doc.Channel.Items[i]
+		if it.PostType != "post" {
+			continue // 页面、附件、菜单等
+		}
+		posts++
+		item, err := w.importPost(it)
+		if err != nil {
+			return nil, err
+		}
+		imp.report.add(item)
+	}
+	if posts == 0 {
+		return nil, errmsg.ErrImportEmpty.WithMsg("WXR 文件中没有文章")
+	}
+	return imp.report, nil
+}
+
+// author 将 WordPress 作者对应到本站用户：先查导入记录，再按邮箱匹配，都没有时新建未激活的用户。
+// 不按用户名匹配，避免 WordPress 的 admin 等用户名被对应到本站无关的账号；用户名已被占用时新用户追加数字后缀。
+// 无法新建时 (缺少邮箱等) 该作者的文章归入导入者名下
+func (w *wxrImport) author(a wxrAuthor) error {
+	login := strings.TrimSpace(a.Login)
+	if login == "" {
+		return nil
+	}
+	id, err := findSource(w.source, SourceUser, login)
+	if err != nil {
+		return err
+	}
+	if id > 0 {
+		w.authors[login] = id
+		return nil
+	}
+
+	email := strings.TrimSpace(a.Email)
+	if email == "" || len(email) > 32 {
+		return nil
+	}
+	var user User
+	err = db.Select("id").Where("email = ?", email).Take(&user).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err == nil {
+		w.authors[login] = user.ID
+		if w.opts.DryRun {
+			return nil
+		}
+		return recordSource(db, w.source, SourceUser, login, user.ID)
+	}
+
+	username, err := w.uniqueUsername(login)
+	if err != nil {
+		return err
+	}
+	if username != truncate(login, 20) {
+		w.report.Warnings = append(w.report.Warnings, fmt.Sprintf("作者 %s 与本站已有用户同名，新建为用户 %s", login, username))
+	}
+	w.report.NewAuthors = append(w.report.NewAuthors, username)
+	if w.opts.DryRun {
+		w.authors[login] = 0
+		return nil
+	}
+
+	// 随机密码，导入的作者需通过找回密码设置自己的密码
+	secret := make([]byte, 16)
+	if _, err := rand.Read(secret); err != nil {
+		return err
+	}
+	user = User{Username: username, Email: email, Password: hex.EncodeToString(secret)}
+	if err := CreateUser(&user); err != nil {
+		return err
+	}
+	if name := truncate(strings.TrimSpace(a.DisplayName), 50); name != "" {
+		if err := db.Model(&Profile{}).Where("user_id = ?", user.ID).Update("name", name).Error; err != nil {
+			return err
+		}
+	}
+	w.authors[login] = user.ID
+	return recordSource(db, w.source, SourceUser, login, user.ID)
+}
+
+// uniqueUsername 返回未被占用的用户名，被占用时依次追加 -2、-3 等后缀 (截断后不超过 20 个字符)，
+// 同时避开本次导入中将要新建的作者 (试运行时这些作者尚未写入数据库)
+func (w *wxrImport) uniqueUsername(login string) (string, error) {
+	username := truncate(login, 20)
+	for i := 2; ; i++ {
+		var count int64
+		if err := db.Unscoped().Model(&User{}).Where("username = ?", username).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 && !slices.Contains(w.report.NewAuthors, username) {
+			return username, nil
+		}
+		suffix := fmt.Sprintf("-%d", i)
+		username = truncate(login, 20-len(suffix)) + suffix
+	}
+}
+
+// importPost 导入一篇文章及其评论。已导入过的文章跳过，只补充新的评论
+func (w *wxrImport) importPost(it *wxrItem) (ImportItem, error) {
+	item := ImportItem{Source: "post " + it.PostID, Title: html.UnescapeString(strings.TrimSpace(it.Title)), Tags: []string{}}
+	switch it.Status {
+	case "trash", "auto-draft", "inherit":
+		item.Action = ImportSkip
+		item.Message = "状态为 " + it.Status + "，不导入"
+		return item, nil
+	}
+
+	articleID, err := findSource(w.source, SourcePost, it.PostID)
+	if err != nil {
+		return item, err
+	}
+	if articleID > 0 {
+		item.Action = ImportSkip
+		item.Message = "已导入"
+		item.ArticleID = articleID
+		item.Comments, err = w.importComments(articleID, it.Comments)
+		return item, err
+	}
+
+	item.Slug = it.PostName
+	if slug, err := url.PathUnescape(it.PostName); err == nil {
+		item.Slug = slug // WordPress 对中文 slug 做了百分号编码
+	}
+	if item.Title == "" {
+		item.Title = item.Slug
+	}
+	item.Title = truncate(item.Title, 100)
+	item.Date = wxrDate(it.PostDate, it.PubDate)
+	item.Draft = it.Status != "publish" && it.Status != "private"
+	seen := map[string]bool{}
+	for _, c := range it.Categories {
+		name := truncate(html.UnescapeString(strings.TrimSpace(c.Name)), 20)
+		if name == "" {
+			continue
+		}
+		switch c.Domain {
+		case "category":
+			// 文章只有一个分类，其余分类作为标签保留
+			if item.Category == "" {
+				item.Category = name
+				continue
+			}
+		case "post_tag":
+		default:
+			continue
+		}
+		if !seen[name] {
+			seen[name] = true
+			item.Tags = append(item.Tags, name)
+		}
+	}
+
+	authorID, ok := w.authors[strings.TrimSpace(it.Creator)]
+	if !ok {
+		authorID = w.opts.AuthorID
+		item.Warnings = append(item.Warnings, fmt.Sprintf("作者 %s 无法导入，归入导入者名下", it.Creator))
+	}
+
+	cid, ok := w.check(&item)
+	if !ok {
+		return item, nil
+	}
+	if w.opts.DryRun {
+		item.Comments = len(importableComments(it.Comments))
+		return item, nil
+	}
+
+	content, err := htmlmd.Convert(wxrAutop(it.encoded("content")))
+	if err != nil {
+		item.Action = ImportFail
+		item.Message = err.Error()
+		return item, nil
+	}
+	desc, _ := htmlmd.Convert(it.encoded("excerpt"))
+	article := &Article{
+		Desc:       truncate(strings.TrimSpace(desc), 200),
+		Content:    content,
+		Pinned:     it.Sticky == "1",
+		Visibility: VisibilityPublic,
+	}
+	switch {
+	case it.Password != "":
+		article.Visibility = VisibilityPassword
+		article.Password = it.Password
+	case it.Status == "private":
+		article.Visibility = VisibilityPrivate
+	}
+	// 文章与导入记录在同一事务中写入，避免记录失败后重新导入时重复创建
+	w.create(&item, article, cid, authorID, func(tx *gorm.DB) error {
+		return recordSource(tx, w.source, SourcePost, it.PostID, article.ID)
+	})
+	if item.Action != ImportCreate {
+		return item, nil
+	}
+
+	item.Comments, err = w.importComments(article.ID, it.Comments)
+	return item, err
+}
+
+// importableComments 返回需要导入的评论：已通过审核的普通评论 (不含 pingback、trackback)，按 ID 升序，
+// 确保父评论先于回复导入
+func importableComments(comments []wxrComment) []wxrComment {
+	result := make([]wxrComment, 0, len(comments))
+	for _, c := range comments {
+		if c.Approved == "1" && (c.Type == "" || c.Type == "comment") {
+			result = append(result, c)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		a, _ := strconv.Atoi(result[i].ID)
+		b, _ := strconv.Atoi(result[j].ID)
+		return a < b
+	})
+	return result
+}
+
+// importComments 导入文章下尚未导入的评论，回复关系通过导入记录映射到 Comment.ParentID，返回新导入的数量
+func (w *wxrImport) importComments(articleID uint, comments []wxrComment) (int, error) {
+	imported := 0
+	for _, c := range importableComments(comments) {
+		id, err := findSource(w.source, SourceComment, c.ID)
+		if err != nil {
+			return imported, err
+		}
+		if id > 0 {
+			continue
+		}
+		imported++
+		if w.opts.DryRun {
+			continue
+		}
+
+		var parentID uint
+		if c.Parent != "" && c.Parent != "0" {
+			if parentID, err = findSource(w.source, SourceComment, c.Parent); err != nil {
+				return imported, err
+			}
+		}
+		date := wxrDate(c.Date, "")
+		original := strings.TrimSpace(c.Content)
+		cleaned, sanitized := sanitizeCommentContent(original)
+		comment := &Comment{
+			BaseModel:   BaseModel{CreatedAt: date, UpdatedAt: date},
+			Commentator: truncate(html.UnescapeString(strings.TrimSpace(c.Author)), 20),
+			Content:     cleaned,
+			ArticleID:   articleID,
+			ParentID:    parentID,
+		}
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(comment).Error; err != nil {
+				return err
+			}
+			if sanitized {
+				if err := recordSanitize(tx, SanitizeTargetComment, comment.ID, original, cleaned); err != nil {
+					return err
+				}
+			}
+			return recordSource(tx, w.source, SourceComment, c.ID, comment.ID)
+		})
+		if err != nil {
+			return imported, err
+		}
+	}
+	return imported, nil
+}
+
+// wxrDate 解析 WordPress 的本地时间，未发布的草稿没有日期时使用 pubDate，都没有时使用当前时间
+func wxrDate(local string, pubDate string) time.Time {
+	if t, err := time.ParseInLocation(wxrDateLayout, strings.TrimSpace(local), time.Local); err == nil && t.Year() > 1 {
+		return t
+	}
+	if t, err := time.Parse(time.RFC1123Z, strings.TrimSpace(pubDate)); err == nil && t.Year() > 1 {
+		return t.Local()
+	}
+	return time.Now()
+}
+
+// wxrAutop 为经典编辑器保存的正文补上段落：WordPress 以空行分段、以换行表示段内换行，
+// 显示时才由 wpautop 转换为 <p> 和 <br>。区块编辑器的正文已包含 <p>，原样返回
+func wxrAutop(content string) string {
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+	if strings.Contains(content, "<p>") || strings.Contains(content, "<p ") {
+		return content
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range wxrPre.FindAllStringIndex(content, -1) {
+		b.WriteString(wxrParagraphs(content[last:loc[0]]))
+		b.WriteString(content[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(wxrParagraphs(content[last:]))
+	return b.String()
+}
+
+// wxrParagraphs 将以空行分隔的文本包裹为段落
+func wxrParagraphs(text string) string {
+	var b strings.Builder
+	for _, p := range wxrParagraph.Split(text, -1) {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		lines := strings.Split(p, "\n")
+		for i := 0; i < len(lines)-1; i++ {
+			if !wxrBlockEnd.MatchString(lines[i]) && !wxrBlockStart.MatchString(lines[i+1]) {
+				lines[i] += "<br>"
+			}
+		}
+		b.WriteString("<p>" + strings.Join(lines, "\n") + "</p>\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"goblog/utils"
+	"goblog/utils/markdown"
+	"strings"
+	"time"
+)
+
+// markdownCacheTpl 是渲染结果的缓存键，以渲染配置的指纹和正文的哈希区分，
+// 正文不变时无需重新渲染；修改清理策略或高亮配色后旧的缓存不再命中
+const markdownCacheTpl = "markdown:%s:%s"
+
+const markdownCacheTTL = 7 * 24 * time.Hour
+
+// renderMarkdown 渲染文章正文，结果按正文哈希缓存在 Redis 中
+func renderMarkdown(content string) (*markdown.Result, error) {
+	sum := sha1.Sum([]byte(content))
+	cacheKey := fmt.Sprintf(markdownCacheTpl, renderFingerprint(), hex.EncodeToString(sum[:]))
+
+	var cached markdown.Result
+	if getCache(cacheKey, &cached) {
+		return &cached, nil
+	}
+
+	result, err := markdown.Render(content)
+	if err != nil {
+		return nil, err
+	}
+	setCache(cacheKey, result, markdownCacheTTL)
+	return result, nil
+}
+
+// renderFingerprint 返回影响渲染结果的配置 (清理策略、iframe 白名单、高亮配色) 的指纹
+func renderFingerprint() string {
+	sum := sha1.Sum([]byte(strings.Join([]string{
+		utils.SanitizeArticle,
+		strings.Join(utils.SanitizeIframeHosts, ","),
+		utils.HighlightStyle,
+	}, "\x00")))
+	return hex.EncodeToString(sum[:4])
+}
+
+// fillRendered 将渲染后的 HTML、目录和字数统计填入文章
+func fillRendered(a *Article) error {
+	result, err := renderMarkdown(a.Content)
+	if err != nil {
+		return err
+	}
+	a.HTML = result.HTML
+	a.Toc = result.Toc
+	a.WordCount = result.WordCount
+	a.ReadingTime = result.ReadingTime
+	return nil
+}
@@ -0,0 +1,250 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"goblog/utils/errmsg"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// relatedCacheTpl 是相关文章排序结果的缓存键: 内容版本号、文章ID
+const relatedCacheTpl = "related:v%d:%d"
+
+const relatedCacheTTL = 24 * time.Hour
+
+// RelatedMax 是相关文章接口最多返回的数量，缓存时按此数量计算
+const RelatedMax = 20
+
+// 相关度各部分的权重: 每个共同标签、同一分类、正文 TF-IDF 余弦相似度 (0~1)
+const (
+	relatedWeightTag      = 1.0
+	relatedWeightCategory = 0.5
+	relatedWeightContent  = 3.0
+)
+
+// relatedTermsMax 是每篇文章参与相似度计算的最多词数 (按 TF-IDF 取最高的部分)，避免长文占用过多内存
+const relatedTermsMax = 200
+
+// RelatedArticle 是一篇相关文章及其相关度得分
+type RelatedArticle struct {
+	Article
+	Score float64 `json:"score"`
+}
+
+// relatedDoc 是一篇文章的标签、分类和归一化后的 TF-IDF 向量
+type relatedDoc struct {
+	id   uint
+	cid  uint
+	tags map[uint]bool
+	vec  map[string]float64
+}
+
+// relatedCorpus 是所有文章的 TF-IDF 向量，内容版本号变化后在下次使用时重建
+type relatedCorpus struct {
+	mu      sync.Mutex
+	version int64
+	built   bool
+	docs    map[uint]*relatedDoc
+}
+
+var corpus = &relatedCorpus{}
+
+// GetRelatedArticles 返回与指定文章最相关的文章，按共同标签、同一分类和正文相似度综合排序
+func GetRelatedArticles(id uint, limit int) ([]RelatedArticle, error) {
+	var article Article
+	if err := db.Select("id").First(&article, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmsg.ErrArticleNotExist
+		}
+		return nil, err
+	}
+
+	version := contentVersion()
+	cacheKey := fmt.Sprintf(relatedCacheTpl, version, id)
+	var scored []scoredID
+	if !getCache(cacheKey, &scored) {
+		docs, err := corpus.get(version)
+		if err != nil {
+			return nil, err
+		}
+		scored = rankRelated(docs, id)
+		setCache(cacheKey, scored, relatedCacheTTL)
+	}
+	if len(scored) > limit {
+		scored = scored[:limit]
+	}
+	if len(scored) == 0 {
+		return []RelatedArticle{}, nil
+	}
+
+	ids := make([]uint, 0, len(scored))
+	for _, s := range scored {
+		ids = append(ids, s.ID)
+	}
+	var articles []Article
+	if err := db.Preload("Category").Preload("Tags").Omit("content").Where("id IN ?", ids).Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	fillViewCounts(articles)
+	byID := make(map[uint]Article, len(articles))
+	for _, a := range articles {
+		byID[a.ID] = a
+	}
+
+	related := make([]RelatedArticle, 0, len(scored))
+	for _, s := range scored {
+		if a, ok := byID[s.ID]; ok {
+			related = append(related, RelatedArticle{Article: a, Score: s.Score})
+		}
+	}
+	return related, nil
+}
+
+// rankRelated 计算其他文章与 id 的相关度，返回得分最高的 RelatedMax 篇
+func rankRelated(docs map[uint]*relatedDoc, id uint) []scoredID {
+	target, ok := docs[id]
+	if !ok {
+		return []scoredID{}
+	}
+
+	scored := []scoredID{}
+	for _, doc := range docs {
+		if doc.id == id {
+			continue
+		}
+		score := 0.0
+		for tag := range doc.tags {
+			if target.tags[tag] {
+				score += relatedWeightTag
+			}
+		}
+		if doc.cid == target.cid {
+			score += relatedWeightCategory
+		}
+		score += relatedWeightContent * cosine(target.vec, doc.vec)
+		if score > 0 {
+			scored = append(scored, scoredID{ID: doc.id, Score: math.Round(score*1000) / 1000})
+		}
+	}
+
+	sort.Slice(scored, func(i, j int) bool {
+		if scored[i].Score != scored[j].Score {
+			return scored[i].Score > scored[j].Score
+		}
+		return scored[i].ID > scored[j].ID
+	})
+	if len(scored) > RelatedMax {
+		scored = scored[:RelatedMax]
+	}
+	return scored
+}
+
+// get 返回指定内容版本的文章向量，版本变化时重新计算
+func (c *relatedCorpus) get(version int64) (map[uint]*relatedDoc, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.built && c.version == version {
+		return c.docs, nil
+	}
+
+	docs, err := buildRelatedDocs()
+	if err != nil {
+		return nil, err
+	}
+	c.docs, c.version, c.built = docs, version, true
+	return docs, nil
+}
+
+// buildRelatedDocs 从数据库加载全部公开文章，计算归一化的 TF-IDF 向量
+func buildRelatedDocs() (map[uint]*relatedDoc, error) {
+	docs := map[uint]*relatedDoc{}
+	tfs := map[uint]map[string]float64{}
+	df := map[string]int{}
+
+	var batch []Article
+	err := filterPublic(db.Select("id", "cid", "title", "content")).Preload("Tags").FindInBatches(&batch, 200, func(_ *gorm.DB, _ int) error {
+		for _, a := range batch {
+			doc := &relatedDoc{id: a.ID, cid: a.Cid, tags: map[uint]bool{}}
+			for _, t := range a.Tags {
+				doc.tags[t.ID] = true
+			}
+			tf := map[string]float64{}
+			for _, t := range tokenize(a.Title + " " + a.Content) {
+				tf[t]++
+			}
+			for t := range tf {
+				df[t]++
+			}
+			docs[a.ID] = doc
+			tfs[a.ID] = tf
+		}
+		return nil
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	n := float64(len(docs))
+	for id, tf := range tfs {
+		vec := make(map[string]float64, len(tf))
+		for t, f := range tf {
+			// 只出现在一篇文章中的词不影响相似度，直接跳过
+			if df[t] < 2 {
+				continue
+			}
+			vec[t] = (1 + math.Log(f)) * math.Log(n/float64(df[t]))
+		}
+		docs[id].vec = normalize(topTerms(vec, relatedTermsMax))
+	}
+	return docs, nil
+}
+
+// topTerms 保留权重最高的 k 个词
+func topTerms(vec map[string]float64, k int) map[string]float64 {
+	if len(vec) <= k {
+		return vec
+	}
+	terms := make([]string, 0, len(vec))
+	for t := range vec {
+		terms = append(terms, t)
+	}
+	sort.Slice(terms, func(i, j int) bool { return vec[terms[i]] > vec[terms[j]] })
+	top := make(map[string]float64, k)
+	for _, t := range terms[:k] {
+		top[t] = vec[t]
+	}
+	return top
+}
+
+// normalize 将向量归一化为单位长度
+func normalize(vec map[string]float64) map[string]float64 {
+	var sum float64
+	for _, w := range vec {
+		sum += w * w
+	}
+	if sum == 0 {
+		return vec
+	}
+	norm := math.Sqrt(sum)
+	for t := range vec {
+		vec[t] /= norm
+	}
+	return vec
+}
+
+// cosine 计算两个单位向量的余弦相似度
+func cosine(a, b map[string]float64) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	var dot float64
+	for t, w := range a {
+		dot += w * b[t]
+	}
+	return dot
+}
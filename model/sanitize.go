@@ -0,0 +1,86 @@
+package model
+
+import (
+	"errors"
+	"goblog/utils/errmsg"
+	"goblog/utils/markdown"
+	"goblog/utils/sanitize"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 被清理内容的类型
+const (
+	SanitizeTargetArticle = "article"
+	SanitizeTargetComment = "comment"
+)
+
+// SanitizeReport 记录一次保存时被清理器修改的内容，供管理员审查
+type SanitizeReport struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	CreatedAt  time.Time `json:"createdAt"`
+	TargetType string    `gorm:"type:varchar(10);not null;index:idx_sanitize_target,priority:1" json:"targetType"`
+	TargetID   uint      `gorm:"not null;index:idx_sanitize_target,priority:2" json:"targetId"`
+	Original   string    `gorm:"type:longtext;not null" json:"original"`
+	Sanitized  string    `gorm:"type:longtext;not null" json:"sanitized"`
+}
+
+// sanitizeArticleContent 清理文章 Markdown 中内嵌的危险 HTML，Markdown 文本和普通标签不受影响
+func sanitizeArticleContent(content string) (string, bool) {
+	return markdown.SanitizeSource(content, sanitize.Article)
+}
+
+// sanitizeCommentContent 按评论策略清理评论内容
+func sanitizeCommentContent(content string) (string, bool) {
+	cleaned := sanitize.Comment(content)
+	return cleaned, sanitize.Changed(content, cleaned)
+}
+
+// sanitizeComments 在返回评论前再次清理，覆盖清理策略启用前保存的旧评论
+func sanitizeComments(comments []Comment) {
+	for i := range comments {
+		comments[i].Content = sanitize.Comment(comments[i].Content)
+	}
+}
+
+// recordSanitize 记录被清理的内容
+func recordSanitize(tx *gorm.DB, targetType string, targetID uint, original string, sanitized string) error {
+	return tx.Create(&SanitizeReport{
+		TargetType: targetType,
+		TargetID:   targetID,
+		Original:   original,
+		Sanitized:  sanitized,
+	}).Error
+}
+
+// GetSanitizeReports 分页查询清理记录，targetType 为空时查询全部
+func GetSanitizeReports(targetType string, pageSize int, pageNum int) ([]SanitizeReport, int64, error) {
+	var reports []SanitizeReport
+	var total int64
+	DB := db.Model(&SanitizeReport{})
+	if targetType != "" {
+		DB = DB.Where("target_type = ?", targetType)
+	}
+
+	if err := DB.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := DB.Order("id desc").Limit(pageSize).Offset((pageNum - 1) * pageSize).Find(&reports).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, err
+	}
+	return reports, total, nil
+}
+
+// DeleteSanitizeReport 删除 (确认) 一条清理记录
+func DeleteSanitizeReport(id uint) error {
+	result := db.Delete(&SanitizeReport{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errmsg.ErrReportNotExist
+	}
+	return nil
+}
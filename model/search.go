@@ -0,0 +1,318 @@
+package model
+
+import (
+	"fmt"
+	"goblog/utils"
+	"html"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// SearchOptions 全文搜索的查询条件
+type SearchOptions struct {
+	Query    string
+	Cid      uint
+	AuthorID uint
+	From     time.Time // 发布时间下限 (含)，零值表示不限
+	To       time.Time // 发布时间上限 (不含)，零值表示不限
+	Viewer   Viewer    // 只返回访问者可以在列表中看到的文章
+	PageSize int
+	PageNum  int
+}
+
+// SearchHit 是一条搜索结果
+type SearchHit struct {
+	Article Article `json:"article"` // 不包含正文，正文摘要见 Snippet
+	Score   float64 `json:"score"`
+	Title   string  `json:"title"`   // 高亮后的标题 (HTML)
+	Snippet string  `json:"snippet"` // 命中位置附近的正文摘要，已高亮 (HTML)
+}
+
+// scoredID 是搜索引擎返回的文章ID及其相关度得分
+type scoredID struct {
+	ID    uint
+	Score float64
+}
+
+// searcher 是全文搜索引擎的抽象，MySQL FULLTEXT 与内存索引各有一个实现
+type searcher interface {
+	search(opts *SearchOptions) ([]scoredID, int64, error)
+	update(id uint) // 文章新增或修改后调用
+	remove(id uint) // 文章删除后调用
+}
+
+var searchEngine searcher = mysqlSearcher{}
+
+const snippetWidth = 120 // 摘要长度 (字符数)
+
+// InitSearch 根据配置初始化全文搜索引擎，需在 InitDb 之后调用
+func InitSearch() {
+	engine := utils.SearchEngine
+	if engine == "auto" {
+		engine = "memory"
+		if utils.Db == "mysql" {
+			engine = "mysql"
+		}
+	}
+
+	if engine == "mysql" {
+		if err := ensureFulltextIndex(); err != nil {
+			log.Fatalf("创建全文索引失败: %v", err)
+		}
+		searchEngine = mysqlSearcher{}
+		return
+	}
+
+	idx := newMemoryIndex()
+	if err := idx.rebuild(); err != nil {
+		log.Fatalf("构建搜索索引失败: %v", err)
+	}
+	searchEngine = idx
+}
+
+// SearchArticles 全文搜索文章，结果按相关度排序
+func SearchArticles(opts *SearchOptions) ([]SearchHit, int64, error) {
+	scored, total, err := searchEngine.search(opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(scored) == 0 {
+		return []SearchHit{}, total, nil
+	}
+
+	ids := make([]uint, 0, len(scored))
+	for _, s := range scored {
+		ids = append(ids, s.ID)
+	}
+	var articles []Article
+	if err := db.Preload("Category").Preload("Tags").Where("id IN ?", ids).Find(&articles).Error; err != nil {
+		return nil, 0, err
+	}
+	fillViewCounts(articles)
+	hideLocked(articles, opts.Viewer)
+	byID := make(map[uint]Article, len(articles))
+	for _, a := range articles {
+		byID[a.ID] = a
+	}
+
+	terms := uniqueTerms(opts.Query)
+	hits := make([]SearchHit, 0, len(scored))
+	for _, s := range scored {
+		article, ok := byID[s.ID]
+		if !ok {
+			continue
+		}
+		hit := SearchHit{
+			Score:   s.Score,
+			Title:   highlight(article.Title, terms),
+			Snippet: snippet(article.Content, terms, snippetWidth),
+		}
+		if hit.Snippet == "" {
+			hit.Snippet = snippet(article.Desc, terms, snippetWidth)
+		}
+		article.Content = ""
+		hit.Article = article
+		hits = append(hits, hit)
+	}
+	return hits, total, nil
+}
+
+// --- MySQL FULLTEXT 实现 ---
+
+// fulltextIndexes 是文章表上需要的全文索引，使用 ngram 解析器以支持中文
+var fulltextIndexes = []struct{ name, columns string }{
+	{"ft_article_title", "title"},
+	{"ft_article_meta", "title, `desc`"},
+	{"ft_article_all", "title, `desc`, content"},
+}
+
+// ensureFulltextIndex 创建缺失的 FULLTEXT 索引
+func ensureFulltextIndex() error {
+	for _, idx := range fulltextIndexes {
+		if db.Migrator().HasIndex(&Article{}, idx.name) {
+			continue
+		}
+		sql := fmt.Sprintf("CREATE FULLTEXT INDEX %s ON article (%s) WITH PARSER ngram", idx.name, idx.columns)
+		if err := db.Exec(sql).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type mysqlSearcher struct{}
+
+// tagMatchSQL 判断文章是否有标签名包含搜索词
+const tagMatchSQL = "EXISTS (SELECT 1 FROM article_tag JOIN tag ON tag.id = article_tag.tag_id WHERE article_tag.article_id = article.id AND tag.name LIKE ?)"
+
+// 全文匹配的字段: 全部字段，或只有标题和摘要
+const (
+	matchAllSQL  = "MATCH(article.title, article.`desc`, article.content) AGAINST(?)"
+	matchMetaSQL = "MATCH(article.title, article.`desc`) AGAINST(?)"
+)
+
+func (mysqlSearcher) search(opts *SearchOptions) ([]scoredID, int64, error) {
+	q := opts.Query
+	like := "%" + q + "%"
+
+	// 非管理员看不到受密码保护文章的正文，这类文章只匹配标题和摘要，避免通过命中结果推断正文
+	matchSQL, matchArgs := matchAllSQL, []any{q}
+	if !opts.Viewer.isAdmin() {
+		matchSQL = "(article.visibility <> ? AND " + matchAllSQL + ") OR (article.visibility = ? AND " + matchMetaSQL + ")"
+		matchArgs = []any{VisibilityPassword, q, VisibilityPassword, q}
+	}
+
+	DB := db.Table("article").
+		Joins("LEFT JOIN category ON category.id = article.cid").
+		Where("article.deleted_at IS NULL").
+		Where(matchSQL+" OR category.name LIKE ? OR "+tagMatchSQL, append(matchArgs, like, like)...)
+	DB = applySearchFilters(DB, opts)
+
+	var total int64
+	if err := DB.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	scoreSQL, scoreArgs := matchAllSQL, []any{q}
+	if !opts.Viewer.isAdmin() {
+		scoreSQL = "IF(article.visibility = ?, " + matchMetaSQL + ", " + matchAllSQL + ")"
+		scoreArgs = []any{VisibilityPassword, q, q}
+	}
+
+	var rows []scoredID
+	score := "MATCH(article.title) AGAINST(?) * 3 + " + scoreSQL +
+		" + IF(category.name LIKE ?, 2, 0) + IF(" + tagMatchSQL + ", 2, 0)"
+	args := append(append([]any{q}, scoreArgs...), like, like)
+	err := DB.Select("article.id AS id, ("+score+") AS score", args...).
+		Order("score DESC, article.id DESC").
+		Limit(opts.PageSize).Offset((opts.PageNum - 1) * opts.PageSize).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return rows, total, nil
+}
+
+func (mysqlSearcher) update(uint) {}
+
+func (mysqlSearcher) remove(uint) {}
+
+// applySearchFilters 附加可见性、分类、作者和时间范围过滤条件
+func applySearchFilters(DB *gorm.DB, opts *SearchOptions) *gorm.DB {
+	DB = filterVisible(DB, opts.Viewer)
+	if opts.Cid > 0 {
+		DB = DB.Where("article.cid = ?", opts.Cid)
+	}
+	if opts.AuthorID > 0 {
+		DB = DB.Where("EXISTS (SELECT 1 FROM user_article WHERE user_article.article_id = article.id AND user_article.user_id = ?)", opts.AuthorID)
+	}
+	if !opts.From.IsZero() {
+		DB = DB.Where("article.created_at >= ?", opts.From)
+	}
+	if !opts.To.IsZero() {
+		DB = DB.Where("article.created_at < ?", opts.To)
+	}
+	return DB
+}
+
+// --- 高亮与摘要 ---
+
+// uniqueTerms 返回查询语句切分后去重的搜索词
+func uniqueTerms(query string) []string {
+	seen := map[string]bool{}
+	var terms []string
+	for _, t := range tokenize(query) {
+		if !seen[t] {
+			seen[t] = true
+			terms = append(terms, t)
+		}
+	}
+	return terms
+}
+
+// matchMask 标记 text 中被任一搜索词覆盖的字符位置 (忽略大小写)
+func matchMask(runes []rune, terms []string) ([]bool, int) {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	mask := make([]bool, len(runes))
+	first := -1
+	for _, term := range terms {
+		t := []rune(term)
+		for i := 0; i+len(t) <= len(lower); i++ {
+			if !runesEqual(lower[i:i+len(t)], t) {
+				continue
+			}
+			for j := i; j < i+len(t); j++ {
+				mask[j] = true
+			}
+			if first < 0 || i < first {
+				first = i
+			}
+		}
+	}
+	return mask, first
+}
+
+func runesEqual(a, b []rune) bool {
+	for i := range b {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// renderHighlight 将 runes[start:end] 转义为 HTML，并用 <em> 包裹命中部分
+func renderHighlight(runes []rune, mask []bool, start, end int) string {
+	var b strings.Builder
+	open := false
+	for i := start; i < end; i++ {
+		if mask[i] != open {
+			if mask[i] {
+				b.WriteString("<em>")
+			} else {
+				b.WriteString("</em>")
+			}
+			open = mask[i]
+		}
+		b.WriteString(html.EscapeString(string(runes[i])))
+	}
+	if open {
+		b.WriteString("</em>")
+	}
+	return b.String()
+}
+
+// highlight 高亮 text 中的所有搜索词，返回转义后的 HTML
+func highlight(text string, terms []string) string {
+	runes := []rune(text)
+	mask, _ := matchMask(runes, terms)
+	return renderHighlight(runes, mask, 0, len(runes))
+}
+
+// snippet 截取 text 中第一个命中位置附近 width 个字符并高亮，没有命中时返回空字符串
+func snippet(text string, terms []string, width int) string {
+	runes := []rune(text)
+	mask, first := matchMask(runes, terms)
+	if first < 0 {
+		return ""
+	}
+
+	start := max(first-width/4, 0)
+	end := min(start+width, len(runes))
+	s := renderHighlight(runes, mask, start, end)
+	if start > 0 {
+		s = "…" + s
+	}
+	if end < len(runes) {
+		s += "…"
+	}
+	return s
+}
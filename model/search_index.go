@@ -0,0 +1,288 @@
+package model
+
+import (
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// 各字段在相关度计算中的权重
+const (
+	weightTitle    = 3.0
+	weightTag      = 2.0
+	weightCategory = 2.0
+	weightDesc     = 1.5
+	weightContent  = 1.0
+)
+
+// BM25 参数
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// memoryDoc 是内存索引中的一篇文章
+type memoryDoc struct {
+	id          uint
+	cid         uint
+	authorID    uint
+	visibility  string
+	visibleRole int
+	draft       bool
+	createdAt   time.Time
+	tf          map[string]float64 // 按字段权重加权后的词频
+	locked      map[string]float64 // 受密码保护文章正文的词频，只在管理员搜索时计入
+	length      float64            // 按字段权重加权后的文档长度
+}
+
+// memoryIndex 是纯 Go 实现的倒排索引，用于不支持 FULLTEXT 的数据库
+type memoryIndex struct {
+	mu       sync.RWMutex
+	docs     map[uint]*memoryDoc
+	postings map[string]map[uint]struct{}
+	totalLen float64
+}
+
+func newMemoryIndex() *memoryIndex {
+	return &memoryIndex{
+		docs:     map[uint]*memoryDoc{},
+		postings: map[string]map[uint]struct{}{},
+	}
+}
+
+// rebuild 从数据库加载全部文章重建索引
+func (idx *memoryIndex) rebuild() error {
+	authors, err := articleAuthors()
+	if err != nil {
+		return err
+	}
+
+	var batch []Article
+	return db.Preload("Category").Preload("Tags").FindInBatches(&batch, 200, func(_ *gorm.DB, _ int) error {
+		for i := range batch {
+			idx.put(newMemoryDoc(&batch[i], authors[batch[i].ID]))
+		}
+		return nil
+	}).Error
+}
+
+// articleAuthors 返回文章ID到作者ID的映射
+func articleAuthors(articleIDs ...uint) (map[uint]uint, error) {
+	var rows []UserArticle
+	DB := db.Model(&UserArticle{})
+	if len(articleIDs) > 0 {
+		DB = DB.Where("article_id IN ?", articleIDs)
+	}
+	if err := DB.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	authors := make(map[uint]uint, len(rows))
+	for _, r := range rows {
+		authors[r.ArticleId] = r.UserId
+	}
+	return authors, nil
+}
+
+func newMemoryDoc(a *Article, authorID uint) *memoryDoc {
+	doc := &memoryDoc{
+		id:          a.ID,
+		cid:         a.Cid,
+		authorID:    authorID,
+		visibility:  a.Visibility,
+		visibleRole: a.VisibleRole,
+		draft:       a.Draft,
+		createdAt:   a.CreatedAt,
+		tf:          map[string]float64{},
+	}
+	addField := func(tf map[string]float64, text string, weight float64) {
+		for _, t := range tokenize(text) {
+			tf[t] += weight
+			doc.length += weight
+		}
+	}
+	addField(doc.tf, a.Title, weightTitle)
+	addField(doc.tf, strings.Join(TagNames(a.Tags), " "), weightTag)
+	addField(doc.tf, a.Category.Name, weightCategory)
+	addField(doc.tf, a.Desc, weightDesc)
+	if a.Visibility == VisibilityPassword {
+		// 非管理员看不到正文，正文中的词不能让文章出现在他们的搜索结果中
+		doc.locked = map[string]float64{}
+		addField(doc.locked, a.Content, weightContent)
+	} else {
+		addField(doc.tf, a.Content, weightContent)
+	}
+	return doc
+}
+
+// put 添加或替换一篇文章
+func (idx *memoryIndex) put(doc *memoryDoc) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.deleteLocked(doc.id)
+	idx.docs[doc.id] = doc
+	idx.totalLen += doc.length
+	for _, tf := range []map[string]float64{doc.tf, doc.locked} {
+		for t := range tf {
+			if idx.postings[t] == nil {
+				idx.postings[t] = map[uint]struct{}{}
+			}
+			idx.postings[t][doc.id] = struct{}{}
+		}
+	}
+}
+
+func (idx *memoryIndex) deleteLocked(id uint) {
+	old, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+	for _, tf := range []map[string]float64{old.tf, old.locked} {
+		for t := range tf {
+			delete(idx.postings[t], id)
+			if len(idx.postings[t]) == 0 {
+				delete(idx.postings, t)
+			}
+		}
+	}
+	idx.totalLen -= old.length
+	delete(idx.docs, id)
+}
+
+func (idx *memoryIndex) update(id uint) {
+	var article Article
+	if err := db.Preload("Category").Preload("Tags").First(&article, id).Error; err != nil {
+		log.Printf("更新搜索索引失败 (文章 %d): %v", id, err)
+		idx.remove(id)
+		return
+	}
+	authors, err := articleAuthors(id)
+	if err != nil {
+		log.Printf("更新搜索索引失败 (文章 %d): %v", id, err)
+	}
+	idx.put(newMemoryDoc(&article, authors[id]))
+}
+
+func (idx *memoryIndex) remove(id uint) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.deleteLocked(id)
+}
+
+func (idx *memoryIndex) search(opts *SearchOptions) ([]scoredID, int64, error) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	n := float64(len(idx.docs))
+	if n == 0 {
+		return nil, 0, nil
+	}
+	avgLen := idx.totalLen / n
+
+	scores := map[uint]float64{}
+	for _, t := range uniqueTerms(opts.Query) {
+		postings := idx.postings[t]
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for id := range postings {
+			doc := idx.docs[id]
+			if !doc.matches(opts) {
+				continue
+			}
+			tf := doc.tf[t]
+			if opts.Viewer.isAdmin() {
+				tf += doc.locked[t]
+			}
+			if tf == 0 {
+				continue
+			}
+			scores[id] += idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*doc.length/avgLen))
+		}
+	}
+
+	results := make([]scoredID, 0, len(scores))
+	for id, score := range scores {
+		results = append(results, scoredID{ID: id, Score: score})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return idx.docs[results[i].ID].createdAt.After(idx.docs[results[j].ID].createdAt)
+	})
+
+	total := int64(len(results))
+	start := min((opts.PageNum-1)*opts.PageSize, len(results))
+	end := min(start+opts.PageSize, len(results))
+	return results[start:end], total, nil
+}
+
+// matches 判断文章是否满足可见性、分类、作者和时间范围过滤条件
+func (doc *memoryDoc) matches(opts *SearchOptions) bool {
+	if !opts.Viewer.canList(doc.visibility, doc.visibleRole, doc.draft) {
+		return false
+	}
+	if opts.Cid > 0 && doc.cid != opts.Cid {
+		return false
+	}
+	if opts.AuthorID > 0 && doc.authorID != opts.AuthorID {
+		return false
+	}
+	if !opts.From.IsZero() && doc.createdAt.Before(opts.From) {
+		return false
+	}
+	if !opts.To.IsZero() && !doc.createdAt.Before(opts.To) {
+		return false
+	}
+	return true
+}
+
+// tokenize 将文本切分为搜索词：拉丁字母和数字按单词切分并转为小写，
+// 中日韩文字按二元组 (bigram) 切分，与 MySQL ngram 解析器默认的 ngram_token_size=2 一致
+func tokenize(text string) []string {
+	var tokens []string
+	var word []rune
+	var cjk []rune
+
+	flushWord := func() {
+		if len(word) > 0 {
+			tokens = append(tokens, string(word))
+			word = word[:0]
+		}
+	}
+	flushCJK := func() {
+		if len(cjk) == 1 {
+			tokens = append(tokens, string(cjk))
+		}
+		for i := 0; i+1 < len(cjk); i++ {
+			tokens = append(tokens, string(cjk[i:i+2]))
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCJK()
+			word = append(word, unicode.ToLower(r))
+		default:
+			flushWord()
+			flushCJK()
+		}
+	}
+	flushWord()
+	flushCJK()
+	return tokens
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
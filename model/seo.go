@@ -0,0 +1,172 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"goblog/utils"
+	"goblog/utils/errmsg"
+	"goblog/utils/seo"
+	"html/template"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const seoListSize = 20 // 首页和分类页 <noscript> 中列出的文章数
+
+// HomePage 返回首页的元信息，正文列出最新文章
+func HomePage() (*seo.Page, error) {
+	var articles []Article
+	if err := filterPublic(db.Select("id", "title")).Order("created_at DESC").Limit(seoListSize).Find(&articles).Error; err != nil {
+		return nil, err
+	}
+
+	p := &seo.Page{
+		SiteName:    utils.SiteTitle,
+		Title:       utils.SiteTitle,
+		Description: utils.SiteDesc,
+		URL:         utils.SiteURL + "/",
+		Type:        "website",
+		FeedURL:     utils.SiteAPI + FeedScope{Kind: FeedScopeSite}.path("rss"),
+		MicropubURL: utils.SiteAPI + "/micropub",
+		JSONLD: map[string]any{
+			"@context":    "https://schema.org",
+			"@type":       "WebSite",
+			"name":        utils.SiteTitle,
+			"description": utils.SiteDesc,
+			"url":         utils.SiteURL + "/",
+		},
+		Heading: utils.SiteTitle,
+		Links:   articleLinks(articles),
+	}
+	if utils.SiteDesc != "" {
+		p.Paragraphs = []string{utils.SiteDesc}
+	}
+	return p, nil
+}
+
+// CategoryPage 返回分类页的元信息，正文列出分类下的最新文章
+func CategoryPage(id uint) (*seo.Page, error) {
+	var cate Category
+	if err := db.First(&cate, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmsg.ErrCateNotExist
+		}
+		return nil, err
+	}
+
+	var articles []Article
+	err := filterPublic(db.Select("id", "title").Where("cid = ?", id)).Order("created_at DESC").Limit(seoListSize).Find(&articles).Error
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/categories/%d", utils.SiteURL, cate.ID)
+	description := fmt.Sprintf("%s 分类下的文章", cate.Name)
+	return &seo.Page{
+		SiteName:    utils.SiteTitle,
+		Title:       cate.Name + " - " + utils.SiteTitle,
+		Description: description,
+		URL:         url,
+		Type:        "website",
+		FeedURL:     utils.SiteAPI + FeedScope{Kind: FeedScopeCategory, ID: cate.ID}.path("rss"),
+		JSONLD: map[string]any{
+			"@context":    "https://schema.org",
+			"@type":       "CollectionPage",
+			"name":        cate.Name,
+			"description": description,
+			"url":         url,
+		},
+		Heading: cate.Name,
+		Links:   articleLinks(articles),
+	}, nil
+}
+
+// ArticlePage 返回文章页的元信息和 BlogPosting 结构化数据，匿名访问者不可见的文章不注入元信息
+func ArticlePage(id uint) (*seo.Page, error) {
+	var article Article
+	if err := db.Preload("Category").Preload("Tags").First(&article, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errmsg.ErrArticleNotExist
+		}
+		return nil, err
+	}
+	if err := checkVisible(&article, Viewer{}, ""); err != nil {
+		return nil, err
+	}
+
+	authors, err := articleAuthors(article.ID)
+	if err != nil {
+		return nil, err
+	}
+	names, err := authorNames([]uint{authors[article.ID]})
+	if err != nil {
+		return nil, err
+	}
+	author := names[authors[article.ID]]
+
+	url := fmt.Sprintf("%s/articles/%d", utils.SiteURL, article.ID)
+	description := articleSummary(&article)
+	image := absoluteURL(article.Img)
+	rendered, err := renderMarkdown(article.Content)
+	if err != nil {
+		return nil, err
+	}
+	tags := TagNames(article.Tags)
+
+	posting := map[string]any{
+		"@context":         "https://schema.org",
+		"@type":            "BlogPosting",
+		"headline":         article.Title,
+		"description":      description,
+		"url":              url,
+		"mainEntityOfPage": url,
+		"datePublished":    article.CreatedAt,
+		"dateModified":     article.UpdatedAt,
+		"articleSection":   article.Category.Name,
+		"keywords":         strings.Join(tags, ","),
+		"wordCount":        rendered.WordCount,
+		"publisher":        map[string]any{"@type": "Organization", "name": utils.SiteTitle},
+	}
+	if author != "" {
+		posting["author"] = map[string]any{"@type": "Person", "name": author}
+	}
+	if image != "" {
+		posting["image"] = image
+	}
+
+	return &seo.Page{
+		SiteName:    utils.SiteTitle,
+		Title:       article.Title + " - " + utils.SiteTitle,
+		Description: description,
+		URL:         url,
+		Image:       image,
+		Type:        "article",
+		FeedURL:     utils.SiteAPI + FeedScope{Kind: FeedScopeSite}.path("rss"),
+		Author:      author,
+		Section:     article.Category.Name,
+		Tags:        tags,
+		Published:   article.CreatedAt,
+		Modified:    article.UpdatedAt,
+		JSONLD:      posting,
+		Heading:     article.Title,
+		Content:     template.HTML(rendered.HTML), // 已由 markdown 包清理
+	}, nil
+}
+
+// articleLinks 将文章列表转换为页面链接
+func articleLinks(articles []Article) []seo.Link {
+	links := make([]seo.Link, 0, len(articles))
+	for _, a := range articles {
+		links = append(links, seo.Link{Title: a.Title, URL: fmt.Sprintf("%s/articles/%d", utils.SiteURL, a.ID)})
+	}
+	return links
+}
+
+// absoluteURL 将站内相对路径转换为绝对地址，已是绝对地址的原样返回
+func absoluteURL(path string) string {
+	if path == "" || strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	return utils.SiteURL + "/" + strings.TrimPrefix(path, "/")
+}
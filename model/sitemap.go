@@ -0,0 +1,240 @@
+package model
+
+import (
+	"fmt"
+	"goblog/utils"
+	"goblog/utils/errmsg"
+	"goblog/utils/sitemap"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sitemapCacheTpl 是站点地图的缓存键: 内容版本号、站点地图名
+const sitemapCacheTpl = "sitemap:v%d:%s"
+
+const sitemapCacheTTL = time.Hour
+
+// SitemapIndexName 是站点地图索引的文件名，其余站点地图命名为 <类型>-<页码>.xml
+const SitemapIndexName = "sitemap.xml"
+
+// sitemapSource 是一类页面的站点地图数据来源，超过 sitemap.MaxURLs 时按页拆分
+type sitemapSource struct {
+	kind  string
+	count func() (int64, error)
+	urls  func(offset, limit int) ([]sitemap.URL, error)
+}
+
+var sitemapSources = []sitemapSource{
+	{"pages", countPages, pageURLs},
+	{"articles", countArticles, articleURLs},
+	{"categories", countCategories, categoryURLs},
+	{"tags", countTags, tagURLs},
+}
+
+// GetSitemap 返回站点地图索引或指定的站点地图，结果缓存在 Redis 中，文章或分类变更后重新生成
+func GetSitemap(name string) (*Document, error) {
+	cacheKey := fmt.Sprintf(sitemapCacheTpl, contentVersion(), name)
+	var cached Document
+	if getCache(cacheKey, &cached) {
+		return &cached, nil
+	}
+
+	var urls []sitemap.URL
+	var err error
+	var render func([]sitemap.URL) ([]byte, error)
+	if name == SitemapIndexName {
+		urls, err = sitemapIndexURLs()
+		render = sitemap.Index
+	} else {
+		urls, err = sitemapURLs(name)
+		render = sitemap.URLSet
+	}
+	if err != nil {
+		return nil, err
+	}
+	body, err := render(urls)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastMod time.Time
+	for _, u := range urls {
+		if u.LastMod.After(lastMod) {
+			lastMod = u.LastMod
+		}
+	}
+	doc := newDocument(body, sitemap.ContentType, lastMod)
+	setCache(cacheKey, doc, sitemapCacheTTL)
+	return doc, nil
+}
+
+// sitemapIndexURLs 列出所有站点地图，lastmod 取各站点地图中最新的页面更新时间
+func sitemapIndexURLs() ([]sitemap.URL, error) {
+	var urls []sitemap.URL
+	for _, src := range sitemapSources {
+		total, err := src.count()
+		if err != nil {
+			return nil, err
+		}
+		for page := 1; int64(page-1)*sitemap.MaxURLs < total; page++ {
+			name := fmt.Sprintf("%s-%d.xml", src.kind, page)
+			pageURLs, err := src.urls((page-1)*sitemap.MaxURLs, sitemap.MaxURLs)
+			if err != nil {
+				return nil, err
+			}
+			entry := sitemap.URL{Loc: utils.SiteURL + "/sitemaps/" + name}
+			for _, u := range pageURLs {
+				if u.LastMod.After(entry.LastMod) {
+					entry.LastMod = u.LastMod
+				}
+			}
+			urls = append(urls, entry)
+		}
+	}
+	return urls, nil
+}
+
+// sitemapURLs 解析形如 articles-2.xml 的站点地图名并返回其中的页面
+func sitemapURLs(name string) ([]sitemap.URL, error) {
+	kind, page, ok := strings.Cut(strings.TrimSuffix(name, ".xml"), "-")
+	n, err := strconv.Atoi(page)
+	if !ok || err != nil || n < 1 || !strings.HasSuffix(name, ".xml") {
+		return nil, errmsg.ErrSitemapNotExist
+	}
+	for _, src := range sitemapSources {
+		if src.kind != kind {
+			continue
+		}
+		urls, err := src.urls((n-1)*sitemap.MaxURLs, sitemap.MaxURLs)
+		if err != nil {
+			return nil, err
+		}
+		if len(urls) == 0 {
+			return nil, errmsg.ErrSitemapNotExist
+		}
+		return urls, nil
+	}
+	return nil, errmsg.ErrSitemapNotExist
+}
+
+// latestArticleUpdate 返回最近一次文章更新的时间
+func latestArticleUpdate() (time.Time, error) {
+	var row struct{ Latest *time.Time }
+	if err := filterPublic(db.Model(&Article{})).Select("MAX(updated_at) AS latest").Scan(&row).Error; err != nil {
+		return time.Time{}, err
+	}
+	if row.Latest == nil {
+		return time.Time{}, nil
+	}
+	return *row.Latest, nil
+}
+
+// --- 各类页面 ---
+
+func countPages() (int64, error) {
+	return 2, nil
+}
+
+// pageURLs 返回首页和分类列表页
+func pageURLs(offset, limit int) ([]sitemap.URL, error) {
+	if offset > 0 {
+		return nil, nil
+	}
+	latest, err := latestArticleUpdate()
+	if err != nil {
+		return nil, err
+	}
+	return []sitemap.URL{
+		{Loc: utils.SiteURL + "/", LastMod: latest},
+		{Loc: utils.SiteURL + "/categories", LastMod: latest},
+	}, nil
+}
+
+func countArticles() (int64, error) {
+	var total int64
+	err := filterPublic(db.Model(&Article{})).Count(&total).Error
+	return total, err
+}
+
+func articleURLs(offset, limit int) ([]sitemap.URL, error) {
+	var articles []Article
+	err := filterPublic(db.Select("id", "updated_at")).Order("id").Offset(offset).Limit(limit).Find(&articles).Error
+	if err != nil {
+		return nil, err
+	}
+	urls := make([]sitemap.URL, 0, len(articles))
+	for _, a := range articles {
+		urls = append(urls, sitemap.URL{
+			Loc:     fmt.Sprintf("%s/articles/%d", utils.SiteURL, a.ID),
+			LastMod: a.UpdatedAt,
+		})
+	}
+	return urls, nil
+}
+
+func countCategories() (int64, error) {
+	var total int64
+	err := db.Model(&Category{}).Count(&total).Error
+	return total, err
+}
+
+// categoryURLs 返回分类页，分类没有更新时间，lastmod 取分类下最新文章的更新时间
+func categoryURLs(offset, limit int) ([]sitemap.URL, error) {
+	var rows []struct {
+		ID      uint
+		LastMod *time.Time
+	}
+	err := db.Table("category").
+		Select("category.id, MAX(article.updated_at) AS last_mod").
+		Joins("LEFT JOIN article ON article.cid = category.id AND article.deleted_at IS NULL AND article.visibility = ? AND article.draft = ?", VisibilityPublic, false).
+		Group("category.id").Order("category.id").
+		Offset(offset).Limit(limit).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	urls := make([]sitemap.URL, 0, len(rows))
+	for _, r := range rows {
+		u := sitemap.URL{Loc: fmt.Sprintf("%s/categories/%d", utils.SiteURL, r.ID)}
+		if r.LastMod != nil {
+			u.LastMod = *r.LastMod
+		}
+		urls = append(urls, u)
+	}
+	return urls, nil
+}
+
+// tagHasArticleSQL 判断标签下是否有未删除的公开文章 (不含草稿)，没有公开文章的标签不写入站点地图
+const tagHasArticleSQL = "EXISTS (SELECT 1 FROM article_tag JOIN article ON article.id = article_tag.article_id WHERE article_tag.tag_id = tag.id AND article.deleted_at IS NULL AND article.visibility = 'public' AND article.draft = false)"
+
+func countTags() (int64, error) {
+	var total int64
+	err := db.Model(&Tag{}).Where(tagHasArticleSQL).Count(&total).Error
+	return total, err
+}
+
+// tagURLs 返回标签页，lastmod 取标签下最新文章的更新时间
+func tagURLs(offset, limit int) ([]sitemap.URL, error) {
+	var rows []struct {
+		Name    string
+		LastMod time.Time
+	}
+	err := db.Table("tag").
+		Select("tag.id, tag.name, MAX(article.updated_at) AS last_mod").
+		Joins("JOIN article_tag ON article_tag.tag_id = tag.id").
+		Joins("JOIN article ON article.id = article_tag.article_id AND article.deleted_at IS NULL AND article.visibility = ? AND article.draft = ?", VisibilityPublic, false).
+		Group("tag.id, tag.name").Order("tag.id").
+		Offset(offset).Limit(limit).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	urls := make([]sitemap.URL, 0, len(rows))
+	for _, r := range rows {
+		urls = append(urls, sitemap.URL{
+			Loc:     utils.SiteURL + "/tags/" + url.PathEscape(r.Name),
+			LastMod: r.LastMod,
+		})
+	}
+	return urls, nil
+}
@@ -0,0 +1,168 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"goblog/utils"
+	"goblog/utils/errmsg"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// slug 的目标类型
+const (
+	SlugTargetArticle  = "article"
+	SlugTargetCategory = "category"
+)
+
+// SlugRedirect 记录修改前的旧 slug，使旧链接可以 301 跳转到新地址
+type SlugRedirect struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	CreatedAt  time.Time `json:"createdAt"`
+	TargetType string    `gorm:"type:varchar(10);not null;uniqueIndex:idx_slug_redirect,priority:1" json:"targetType"`
+	OldSlug    string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_slug_redirect,priority:2" json:"oldSlug"`
+	TargetID   uint      `gorm:"not null" json:"targetId"`
+}
+
+// slugTables 是带有 slug 列的表及其唯一索引名
+var slugTables = []struct{ targetType, index string }{
+	{SlugTargetArticle, "idx_article_slug"},
+	{SlugTargetCategory, "idx_category_slug"},
+}
+
+// migrateSlugs 为没有 slug 的旧数据生成 slug，然后创建唯一索引。
+// 唯一索引不能通过 AutoMigrate 创建，因为新增列时所有旧数据的 slug 都为空
+func migrateSlugs() error {
+	var articles []Article
+	if err := db.Unscoped().Select("id", "title").Where("slug = ''").Find(&articles).Error; err != nil {
+		return err
+	}
+	for _, a := range articles {
+		slug, err := uniqueSlug(db, SlugTargetArticle, a.Title, a.ID)
+		if err != nil {
+			return err
+		}
+		if err := db.Unscoped().Model(&Article{}).Where("id = ?", a.ID).UpdateColumn("slug", slug).Error; err != nil {
+			return err
+		}
+	}
+
+	var cates []Category
+	if err := db.Where("slug = ''").Find(&cates).Error; err != nil {
+		return err
+	}
+	for _, c := range cates {
+		slug, err := uniqueSlug(db, SlugTargetCategory, c.Name, c.ID)
+		if err != nil {
+			return err
+		}
+		if err := db.Model(&Category{}).Where("id = ?", c.ID).UpdateColumn("slug", slug).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, t := range slugTables {
+		if db.Migrator().HasIndex(t.targetType, t.index) {
+			continue
+		}
+		sql := fmt.Sprintf("CREATE UNIQUE INDEX %s ON %s (slug)", t.index, t.targetType)
+		if err := db.Exec(sql).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// slugTaken 判断 slug 是否已被除 excludeID 以外的记录占用 (包括已软删除的文章)
+func slugTaken(tx *gorm.DB, targetType string, slug string, excludeID uint) (bool, error) {
+	var count int64
+	err := tx.Unscoped().Table(targetType).Where("slug = ? AND id != ?", slug, excludeID).Count(&count).Error
+	return count > 0, err
+}
+
+// uniqueSlug 根据 text 生成一个未被占用的 slug，冲突时追加数字后缀
+func uniqueSlug(tx *gorm.DB, targetType string, text string, excludeID uint) (string, error) {
+	base := utils.Slugify(text)
+	if base == "" {
+		base = targetType
+	}
+
+	slug := base
+	for i := 2; ; i++ {
+		taken, err := slugTaken(tx, targetType, slug, excludeID)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return slug, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// resolveSlug 确定保存时使用的 slug：
+// 用户指定了 slug 时规范化后使用，被占用则报错；未指定时新建记录根据 fallback 自动生成，编辑记录保留原 slug
+func resolveSlug(tx *gorm.DB, targetType string, requested string, fallback string, id uint, current string) (string, error) {
+	if requested == "" {
+		if current != "" {
+			return current, nil
+		}
+		return uniqueSlug(tx, targetType, fallback, id)
+	}
+
+	slug := utils.Slugify(requested)
+	if slug == "" {
+		return "", errmsg.ErrSlugInvalid
+	}
+	taken, err := slugTaken(tx, targetType, slug, id)
+	if err != nil {
+		return "", err
+	}
+	if taken {
+		return "", errmsg.ErrSlugUsed
+	}
+	return slug, nil
+}
+
+// recordSlugChange 在 slug 变化时记录旧 slug 的跳转，并清除与新 slug 同名的旧跳转
+func recordSlugChange(tx *gorm.DB, targetType string, id uint, oldSlug string, newSlug string) error {
+	if oldSlug == newSlug {
+		return nil
+	}
+	if err := tx.Where("target_type = ? AND old_slug = ?", targetType, newSlug).Delete(&SlugRedirect{}).Error; err != nil {
+		return err
+	}
+	if oldSlug == "" {
+		return nil
+	}
+	redirect := SlugRedirect{TargetType: targetType, OldSlug: oldSlug, TargetID: id}
+	return tx.Where(SlugRedirect{TargetType: targetType, OldSlug: oldSlug}).
+		Assign(SlugRedirect{TargetID: id}).FirstOrCreate(&redirect).Error
+}
+
+// FindSlugRedirect 查找旧 slug 对应记录的当前 slug
+func FindSlugRedirect(targetType string, oldSlug string) (string, error) {
+	var redirect SlugRedirect
+	err := db.Where("target_type = ? AND old_slug = ?", targetType, oldSlug).First(&redirect).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", errmsg.ErrSlugNotExist
+		}
+		return "", err
+	}
+
+	var current struct{ Slug string }
+	DB := db.Table(targetType).Select("slug").Where("id = ?", redirect.TargetID)
+	if targetType == SlugTargetArticle {
+		DB = DB.Where("deleted_at IS NULL")
+	}
+	err = DB.Take(&current).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", errmsg.ErrSlugNotExist
+		}
+		return "", err
+	}
+	return current.Slug, nil
+}
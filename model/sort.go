@@ -0,0 +1,33 @@
+package model
+
+import "gorm.io/gorm"
+
+// 文章列表的排序方式
+const (
+	SortNewest   = "newest"   // 最新发布
+	SortOldest   = "oldest"   // 最早发布
+	SortViews    = "views"    // 阅读最多
+	SortComments = "comments" // 评论最多
+	SortUpdated  = "updated"  // 最近更新
+)
+
+var sortOrders = map[string]string{
+	SortNewest:   "article.created_at DESC",
+	SortOldest:   "article.created_at ASC",
+	SortViews:    "article.view_count DESC",
+	SortComments: "(SELECT COUNT(*) FROM comment WHERE comment.article_id = article.id AND comment.deleted_at IS NULL) DESC",
+	SortUpdated:  "article.updated_at DESC",
+}
+
+// orderArticles 为文章列表添加排序: pinned 为 true 时置顶文章在最前，
+// 其次按手动排序权重从高到低，最后按 sort 指定的方式 (默认最新发布)
+func orderArticles(DB *gorm.DB, sort string, pinned bool) *gorm.DB {
+	if pinned {
+		DB = DB.Order("article.pinned DESC")
+	}
+	order, ok := sortOrders[sort]
+	if !ok {
+		order = sortOrders[SortNewest]
+	}
+	return DB.Order("article.sort_weight DESC").Order(order).Order("article.id DESC")
+}
@@ -0,0 +1,389 @@
+package model
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"goblog/utils"
+	"goblog/utils/feed"
+	"goblog/utils/sitegen"
+	"goblog/utils/sitemap"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const sitePageSize = 10 // 静态站点列表页每页的文章数
+
+// SiteReport 是静态站点的生成结果
+type SiteReport struct {
+	Articles     int      `json:"articles"`
+	Pages        int      `json:"pages"` // 生成的 HTML 页面数
+	Media        int      `json:"media"`
+	MissingMedia []string `json:"missingMedia,omitempty"`
+}
+
+// BuildSite 将公开文章渲染为静态站点并写入目录 dir，已有的同名文件会被覆盖
+func BuildSite(dir string, opts ExportOptions) (*SiteReport, error) {
+	return buildSite(dirExport(dir), opts)
+}
+
+// BuildSiteArchive 将公开文章渲染为静态站点，打包为 ZIP 压缩包写入 w
+func BuildSiteArchive(w io.Writer, opts ExportOptions) (*SiteReport, error) {
+	zw := zip.NewWriter(w)
+	report, err := buildSite(zipExport{zw: zw}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return report, zw.Close()
+}
+
+// staticSite 是一次静态站点生成。页面路径与前台一致 (articles/<id>/、categories/<id>/)，
+// 并补充了标签页，每个页面写为目录下的 index.html
+type staticSite struct {
+	out     exportWriter
+	media   *exportMedia
+	report  *SiteReport
+	site    sitegen.Site
+	summary map[uint]sitegen.Summary // 文章 ID -> 列表项
+	order   []uint                   // 首页的文章顺序
+	links   map[uint]sitemap.URL     // 文章 ID -> 站点地图条目
+}
+
+func buildSite(out exportWriter, opts ExportOptions) (*SiteReport, error) {
+	s := &staticSite{
+		out:     out,
+		media:   newExportMedia(out, opts.Media),
+		report:  &SiteReport{},
+		site:    sitegen.Site{Title: utils.SiteTitle, Description: utils.SiteDesc, URL: utils.SiteURL},
+		summary: map[uint]sitegen.Summary{},
+		links:   map[uint]sitemap.URL{},
+	}
+
+	var articles []Article
+	err := orderArticles(filterPublic(db.Model(&Article{}).Omit("content")), SortNewest, true).
+		Preload("Category").Preload("Tags").Find(&articles).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range articles {
+		s.order = append(s.order, a.ID)
+		s.summary[a.ID] = sitegen.Summary{
+			Title:    a.Title,
+			URL:      articlePath(a.ID),
+			Summary:  a.Desc,
+			Date:     a.CreatedAt,
+			Pinned:   a.Pinned,
+			Category: sitegen.Link{Title: a.Category.Name, URL: categoryPath(a.Cid)},
+			Tags:     tagLinks(a.Tags),
+		}
+	}
+
+	if err := s.articles(); err != nil {
+		return nil, err
+	}
+	if err := s.list("", utils.SiteTitle, utils.SiteTitle, s.order); err != nil {
+		return nil, err
+	}
+	if err := s.categories(articles); err != nil {
+		return nil, err
+	}
+	if err := s.tags(articles); err != nil {
+		return nil, err
+	}
+	if err := s.feed(); err != nil {
+		return nil, err
+	}
+	if err := s.sitemap(); err != nil {
+		return nil, err
+	}
+	if err := out.write(sitegen.StylesheetName, []byte(sitegen.Stylesheet)); err != nil {
+		return nil, err
+	}
+
+	s.report.Media = s.media.count()
+	s.report.MissingMedia = s.media.missing
+	return s.report, nil
+}
+
+func articlePath(id uint) string  { return fmt.Sprintf("articles/%d/index.html", id) }
+func categoryPath(id uint) string { return fmt.Sprintf("categories/%d/index.html", id) }
+func tagPath(id uint) string      { return fmt.Sprintf("tags/%d/index.html", id) }
+
+func tagLinks(tags []Tag) []sitegen.Link {
+	links := make([]sitegen.Link, 0, len(tags))
+	for _, t := range tags {
+		links = append(links, sitegen.Link{Title: t.Name, URL: tagPath(t.ID)})
+	}
+	return links
+}
+
+// canonical 返回页面在线上站点的地址，与前台的路由一致
+func canonical(file string) string {
+	return utils.SiteURL + "/" + strings.TrimSuffix(strings.TrimSuffix(file, "index.html"), "/")
+}
+
+// render 渲染页面并写入 file，Root 根据 file 的目录层级计算
+func (s *staticSite) render(file string, p *sitegen.Page) error {
+	p.Site = s.site
+	p.Root = strings.Repeat("../", strings.Count(file, "/"))
+	var b bytes.Buffer
+	if err := sitegen.Render(&b, p); err != nil {
+		return err
+	}
+	s.report.Pages++
+	return s.out.write(file, b.Bytes())
+}
+
+// articles 分批渲染文章页，上一篇、下一篇按发布时间排列
+func (s *staticSite) articles() error {
+	chrono := append([]uint(nil), s.order...)
+	sort.SliceStable(chrono, func(i, j int) bool {
+		a, b := s.summary[chrono[i]], s.summary[chrono[j]]
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		return chrono[i] < chrono[j]
+	})
+	prev := make(map[uint]*sitegen.Link, len(chrono))
+	next := make(map[uint]*sitegen.Link, len(chrono))
+	for i, id := range chrono {
+		if i > 0 {
+			p := s.summary[chrono[i-1]]
+			prev[id] = &sitegen.Link{Title: p.Title, URL: p.URL}
+		}
+		if i < len(chrono)-1 {
+			n := s.summary[chrono[i+1]]
+			next[id] = &sitegen.Link{Title: n.Title, URL: n.URL}
+		}
+	}
+
+	authors, err := articleAuthors(s.order...)
+	if err != nil {
+		return err
+	}
+	userIDs := make([]uint, 0, len(authors))
+	for _, uid := range authors {
+		userIDs = append(userIDs, uid)
+	}
+	names, err := authorNames(userIDs)
+	if err != nil {
+		return err
+	}
+
+	var batch []Article
+	return filterPublic(db.Model(&Article{})).FindInBatches(&batch, 100, func(tx *gorm.DB, _ int) error {
+		for i := range batch {
+			a := &batch[i]
+			file := articlePath(a.ID)
+			root := strings.Repeat("../", strings.Count(file, "/"))
+			rendered, err := renderMarkdown(a.Content)
+			if err != nil {
+				return err
+			}
+			comments, err := s.comments(a.ID)
+			if err != nil {
+				return err
+			}
+
+			summary := s.summary[a.ID]
+			image := s.media.rewrite(a.Img, root)
+			if image != "" && image == a.Img {
+				image = absoluteURL(image)
+			}
+			err = s.render(file, &sitegen.Page{
+				Title:       a.Title + " - " + utils.SiteTitle,
+				Description: articleSummary(a),
+				Canonical:   canonical(file),
+				Heading:     a.Title,
+				Article: &sitegen.Article{
+					Content:  template.HTML(s.media.rewrite(rendered.HTML, root)), // 已由 markdown 包清理
+					Image:    image,
+					Author:   names[authors[a.ID]],
+					Date:     a.CreatedAt,
+					Updated:  a.UpdatedAt,
+					Category: summary.Category,
+					Tags:     summary.Tags,
+					Prev:     prev[a.ID],
+					Next:     next[a.ID],
+					Comments: comments,
+				},
+			})
+			if err != nil {
+				return err
+			}
+			s.report.Articles++
+			s.links[a.ID] = sitemap.URL{Loc: canonical(file), LastMod: a.UpdatedAt}
+		}
+		return nil
+	}).Error
+}
+
+// comments 返回文章的评论，回复紧跟在被回复的评论之后
+func (s *staticSite) comments(articleID uint) ([]sitegen.Comment, error) {
+	var comments []Comment
+	if err := db.Where("article_id = ?", articleID).Order("id").Find(&comments).Error; err != nil {
+		return nil, err
+	}
+	sanitizeComments(comments)
+
+	replies := map[uint][]Comment{}
+	exists := make(map[uint]bool, len(comments))
+	for _, c := range comments {
+		exists[c.ID] = true
+	}
+	var roots []Comment
+	for _, c := range comments {
+		if c.ParentID != 0 && exists[c.ParentID] {
+			replies[c.ParentID] = append(replies[c.ParentID], c)
+		} else {
+			roots = append(roots, c)
+		}
+	}
+
+	result := make([]sitegen.Comment, 0, len(comments))
+	var walk func(c Comment, reply bool)
+	walk = func(c Comment, reply bool) {
+		result = append(result, sitegen.Comment{Commentator: c.Commentator, Content: template.HTML(c.Content), Date: c.CreatedAt, Reply: reply})
+		for _, r := range replies[c.ID] {
+			walk(r, true)
+		}
+	}
+	for _, c := range roots {
+		walk(c, false)
+	}
+	return result, nil
+}
+
+// list 分页渲染文章列表，第一页写入 dir/index.html，其余写入 dir/page/<n>/index.html
+func (s *staticSite) list(dir string, title string, heading string, ids []uint) error {
+	pages := (len(ids) + sitePageSize - 1) / sitePageSize
+	pages = max(pages, 1)
+	pagePath := func(n int) string {
+		if n == 1 {
+			return dir + "index.html"
+		}
+		return fmt.Sprintf("%spage/%d/index.html", dir, n)
+	}
+
+	for n := 1; n <= pages; n++ {
+		end := min(n*sitePageSize, len(ids))
+		page := &sitegen.Page{
+			Title:     title,
+			Canonical: canonical(pagePath(n)),
+			Heading:   heading,
+			Articles:  make([]sitegen.Summary, 0, end-(n-1)*sitePageSize),
+		}
+		if dir == "" {
+			page.Description = utils.SiteDesc
+		}
+		for _, id := range ids[(n-1)*sitePageSize : end] {
+			page.Articles = append(page.Articles, s.summary[id])
+		}
+		if n > 1 {
+			page.Prev = pagePath(n - 1)
+		}
+		if n < pages {
+			page.Next = pagePath(n + 1)
+		}
+		if err := s.render(pagePath(n), page); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// categories 渲染分类索引页和各分类的文章列表
+func (s *staticSite) categories(articles []Article) error {
+	var cates []Category
+	if err := db.Order("id").Find(&cates).Error; err != nil {
+		return err
+	}
+	ids := map[uint][]uint{}
+	for _, a := range articles {
+		ids[a.Cid] = append(ids[a.Cid], a.ID)
+	}
+
+	index := &sitegen.Page{Title: "分类 - " + utils.SiteTitle, Canonical: canonical("categories/index.html"), Heading: "分类"}
+	for _, c := range cates {
+		if len(ids[c.ID]) == 0 {
+			continue
+		}
+		index.Links = append(index.Links, sitegen.Link{Title: c.Name, URL: categoryPath(c.ID), Count: int64(len(ids[c.ID]))})
+		if err := s.list(fmt.Sprintf("categories/%d/", c.ID), c.Name+" - "+utils.SiteTitle, c.Name, ids[c.ID]); err != nil {
+			return err
+		}
+	}
+	return s.render("categories/index.html", index)
+}
+
+// tags 渲染标签索引页和各标签的文章列表
+func (s *staticSite) tags(articles []Article) error {
+	var tags []Tag
+	ids := map[uint][]uint{}
+	for _, a := range articles {
+		for _, t := range a.Tags {
+			if len(ids[t.ID]) == 0 {
+				tags = append(tags, t)
+			}
+			ids[t.ID] = append(ids[t.ID], a.ID)
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if len(ids[tags[i].ID]) != len(ids[tags[j].ID]) {
+			return len(ids[tags[i].ID]) > len(ids[tags[j].ID])
+		}
+		return tags[i].Name < tags[j].Name
+	})
+
+	index := &sitegen.Page{Title: "标签 - " + utils.SiteTitle, Canonical: canonical("tags/index.html"), Heading: "标签"}
+	for _, t := range tags {
+		index.Links = append(index.Links, sitegen.Link{Title: "#" + t.Name, URL: tagPath(t.ID), Count: int64(len(ids[t.ID]))})
+		if err := s.list(fmt.Sprintf("tags/%d/", t.ID), "#"+t.Name+" - "+utils.SiteTitle, "#"+t.Name, ids[t.ID]); err != nil {
+			return err
+		}
+	}
+	return s.render("tags/index.html", index)
+}
+
+// feed 生成全站 RSS，订阅地址指向静态站点中的 feed.xml
+func (s *staticSite) feed() error {
+	f, err := buildFeed(FeedScope{Kind: FeedScopeSite}, feed.FormatRSS)
+	if err != nil {
+		return err
+	}
+	f.FeedURL = utils.SiteURL + "/feed.xml"
+	body, err := feed.RSS(f)
+	if err != nil {
+		return err
+	}
+	return s.out.write("feed.xml", body)
+}
+
+// sitemap 生成包含首页、分类页和文章页的站点地图
+func (s *staticSite) sitemap() error {
+	var latest time.Time
+	for _, u := range s.links {
+		if u.LastMod.After(latest) {
+			latest = u.LastMod
+		}
+	}
+	urls := []sitemap.URL{
+		{Loc: utils.SiteURL + "/", LastMod: latest},
+		{Loc: utils.SiteURL + "/categories", LastMod: latest},
+	}
+	for _, id := range s.order {
+		if u, ok := s.links[id]; ok {
+			urls = append(urls, u)
+		}
+	}
+	body, err := sitemap.URLSet(urls)
+	if err != nil {
+		return err
+	}
+	return s.out.write(SitemapIndexName, body)
+}
@@ -0,0 +1,270 @@
+package model
+
+import (
+	"errors"
+	"goblog/utils"
+	"goblog/utils/errmsg"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 回收站中的内容类型
+const (
+	TrashArticles = "articles"
+	TrashComments = "comments"
+	TrashUsers    = "users"
+)
+
+// trashCleanInterval 是回收站过期清理任务的执行间隔
+const trashCleanInterval = time.Hour
+
+// trashModels 是各类型对应的模型
+var trashModels = map[string]any{
+	TrashArticles: &Article{},
+	TrashComments: &Comment{},
+	TrashUsers:    &User{},
+}
+
+// trashQuery 返回某类型已软删除记录的查询
+func trashQuery(tx *gorm.DB, kind string) (*gorm.DB, error) {
+	m, ok := trashModels[kind]
+	if !ok {
+		return nil, errmsg.ErrTrashKind
+	}
+	return tx.Unscoped().Model(m).Where("deleted_at IS NOT NULL"), nil
+}
+
+// GetTrash 分页查询回收站中的内容，按删除时间倒序
+func GetTrash(kind string, pageSize int, pageNum int) (any, int64, error) {
+	DB, err := trashQuery(db, kind)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := DB.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	DB = DB.Order("deleted_at DESC").Limit(pageSize).Offset((pageNum - 1) * pageSize)
+	switch kind {
+	case TrashArticles:
+		var articles []Article
+		err = DB.Omit("content").Find(&articles).Error
+		return articles, total, err
+	case TrashComments:
+		var comments []Comment
+		err = DB.Find(&comments).Error
+		return comments, total, err
+	default:
+		var users []User
+		err = DB.Find(&users).Error
+		return users, total, err
+	}
+}
+
+// findTrash 查找回收站中的一条记录
+func findTrash(tx *gorm.DB, kind string, id uint, dest any) error {
+	DB, err := trashQuery(tx, kind)
+	if err != nil {
+		return err
+	}
+	if err := DB.Where("id = ?", id).Take(dest).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errmsg.ErrTrashNotExist
+		}
+		return err
+	}
+	return nil
+}
+
+// RestoreTrash 从回收站恢复内容。恢复文章时一并恢复随文章删除的评论；
+// 恢复评论时所属文章必须未被删除
+func RestoreTrash(kind string, id uint) error {
+	switch kind {
+	case TrashArticles:
+		err := db.Transaction(func(tx *gorm.DB) error {
+			return restoreArticle(tx, id)
+		})
+		if err != nil {
+			return err
+		}
+		searchEngine.update(id)
+		invalidateContentCache()
+		return nil
+	case TrashComments:
+		var comment Comment
+		if err := findTrash(db, kind, id, &comment); err != nil {
+			return err
+		}
+		if err := db.Select("id").First(&Article{}, comment.ArticleID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errmsg.ErrArticleNotExist.WithMsg("评论所属的文章已被删除，请先恢复文章")
+			}
+			return err
+		}
+		return db.Unscoped().Model(&comment).UpdateColumn("deleted_at", nil).Error
+	default:
+		var user User
+		if err := findTrash(db, kind, id, &user); err != nil {
+			return err
+		}
+		return db.Unscoped().Model(&user).UpdateColumn("deleted_at", nil).Error
+	}
+}
+
+// restoreArticle 在事务中从回收站恢复文章及随文章删除的评论
+func restoreArticle(tx *gorm.DB, id uint) error {
+	var article Article
+	if err := findTrash(tx, TrashArticles, id, &article); err != nil {
+		return err
+	}
+	err := tx.Unscoped().Model(&Comment{}).
+		Where("article_id = ? AND deleted_at = ?", id, article.DeletedAt).
+		UpdateColumn("deleted_at", nil).Error
+	if err != nil {
+		return err
+	}
+	return tx.Unscoped().Model(&article).UpdateColumn("deleted_at", nil).Error
+}
+
+// PurgeTrash 彻底删除回收站中的内容及其关联记录，无法恢复
+func PurgeTrash(kind string, id uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		switch kind {
+		case TrashArticles:
+			var article Article
+			if err := findTrash(tx, kind, id, &article); err != nil {
+				return err
+			}
+			return purgeArticle(tx, id)
+		case TrashComments:
+			var comment Comment
+			if err := findTrash(tx, kind, id, &comment); err != nil {
+				return err
+			}
+			return purgeComments(tx, []uint{id})
+		default:
+			var user User
+			if err := findTrash(tx, kind, id, &user); err != nil {
+				return err
+			}
+			return purgeUser(tx, id)
+		}
+	})
+}
+
+// purgeArticle 彻底删除文章、文章下的所有评论以及标签、作者、系列、回应等关联记录
+func purgeArticle(tx *gorm.DB, id uint) error {
+	var commentIDs []uint
+	if err := tx.Unscoped().Model(&Comment{}).Where("article_id = ?", id).Pluck("id", &commentIDs).Error; err != nil {
+		return err
+	}
+	if err := purgeComments(tx, commentIDs); err != nil {
+		return err
+	}
+
+	if err := tx.Where("target_type = ? AND target_id = ?", ReactionTargetArticle, id).Delete(&Reaction{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("target_type = ? AND target_id = ?", SanitizeTargetArticle, id).Delete(&SanitizeReport{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("target_type = ? AND target_id = ?", SlugTargetArticle, id).Delete(&SlugRedirect{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("article_id = ?", id).Delete(&SeriesArticle{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("article_id = ?", id).Delete(&UserArticle{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Exec("DELETE FROM article_tag WHERE article_id = ?", id).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("kind = ? AND local_id = ?", SourcePost, id).Delete(&ImportSource{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Delete(&Article{}, id).Error
+}
+
+// purgeComments 彻底删除评论及其回应和清理记录
+func purgeComments(tx *gorm.DB, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := tx.Where("target_type = ? AND target_id IN ?", ReactionTargetComment, ids).Delete(&Reaction{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("target_type = ? AND target_id IN ?", SanitizeTargetComment, ids).Delete(&SanitizeReport{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("kind = ? AND local_id IN ?", SourceComment, ids).Delete(&ImportSource{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Where("id IN ?", ids).Delete(&Comment{}).Error
+}
+
+// purgeUser 彻底删除用户及其个人信息、作者关联和回应，用户的文章保留
+func purgeUser(tx *gorm.DB, id uint) error {
+	if err := tx.Unscoped().Where("user_id = ?", id).Delete(&Profile{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("user_id = ?", id).Delete(&UserArticle{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("user_id = ?", id).Delete(&Reaction{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("kind = ? AND local_id = ?", SourceUser, id).Delete(&ImportSource{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("user_id = ?", id).Delete(&AppPassword{}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Delete(&User{}, id).Error
+}
+
+// PurgeExpiredTrash 彻底删除在 before 之前移入回收站的内容，返回删除的条数
+func PurgeExpiredTrash(before time.Time) (int, error) {
+	purged := 0
+	for _, kind := range []string{TrashArticles, TrashComments, TrashUsers} {
+		DB, _ := trashQuery(db, kind)
+		var ids []uint
+		if err := DB.Where("deleted_at < ?", before).Pluck("id", &ids).Error; err != nil {
+			return purged, err
+		}
+		for _, id := range ids {
+			err := PurgeTrash(kind, id)
+			if errors.Is(err, errmsg.ErrTrashNotExist) {
+				continue // 已被其他请求彻底删除
+			}
+			if err != nil {
+				return purged, err
+			}
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// InitTrashCleaner 启动后台任务，定期彻底删除超过保留期的回收站内容，保留期为 0 时不启动。需在 InitDb 之后调用
+func InitTrashCleaner() {
+	if utils.TrashRetention <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(trashCleanInterval)
+		defer ticker.Stop()
+		for ; ; <-ticker.C {
+			n, err := PurgeExpiredTrash(time.Now().Add(-utils.TrashRetention))
+			if err != nil {
+				log.Printf("清理回收站失败: %v", err)
+			} else if n > 0 {
+				log.Printf("已彻底删除 %d 条超过保留期的回收站内容", n)
+			}
+		}
+	}()
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// VersionConflict 表示修改时提交的版本号与服务器上的当前版本不一致，即内容已被他人修改
+type VersionConflict struct {
+	Current uint
+}
+
+func (e *VersionConflict) Error() string {
+	return fmt.Sprintf("版本冲突，当前版本为 %d", e.Current)
+}
+
+// updateVersioned 以乐观锁方式更新满足 query 条件的一条记录，返回更新后的版本号。
+// version 为 0 时不校验版本；否则只有当前版本等于 version 时才更新，不相等时返回 *VersionConflict。
+// 记录不存在时返回 gorm.ErrRecordNotFound
+func updateVersioned(tx *gorm.DB, model any, query string, arg any, version uint, updates map[string]any) (uint, error) {
+	DB := tx.Model(model).Where(query, arg)
+	if version > 0 {
+		DB = DB.Where("version = ?", version)
+	}
+	updates["version"] = gorm.Expr("version + 1")
+	result := DB.Updates(updates)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	var row struct{ Version uint }
+	if err := tx.Model(model).Select("version").Where(query, arg).Take(&row).Error; err != nil {
+		return 0, err
+	}
+	// 版本号每次更新都会变化，没有受影响的行说明版本不匹配
+	if result.RowsAffected == 0 {
+		return 0, &VersionConflict{Current: row.Version}
+	}
+	return row.Version, nil
+}
@@ -0,0 +1,239 @@
+package model
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"goblog/utils"
+	"goblog/utils/errmsg"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// Redis 中与阅读量相关的键
+const (
+	viewPendingKey  = "article:views:pending"  // 尚未写回数据库的阅读量增量 (hash: 文章ID -> 增量)
+	viewFlushingKey = "article:views:flushing" // 正在写回数据库的增量
+	viewDailyKeyTpl = "article:views:day:%s"   // 每日阅读量排行 (zset)，用于计算热门文章
+	viewDedupKeyTpl = "article:views:seen:%d:%s"
+	viewPopularTpl  = "article:views:popular:%s" // 热门排行的合并结果缓存
+)
+
+// 热门排行统计周期
+var popularPeriods = map[string]int{
+	"day":   1,
+	"week":  7,
+	"month": 30,
+}
+
+// popularBatchMin 是读取热门排行时每批的最少条数
+const popularBatchMin = 20
+
+// InitViewCounter 启动后台任务，定期将 Redis 中的阅读量写回数据库，需在 InitDb 和 InitRedis 之后调用
+func InitViewCounter() {
+	go func() {
+		ticker := time.NewTicker(utils.ViewFlushInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := FlushViewCounts(); err != nil {
+				log.Printf("阅读量写回数据库失败: %v", err)
+			}
+		}
+	}()
+}
+
+// VisitorID 根据客户端 IP 和 User-Agent 生成访客标识，用于阅读量去重
+func VisitorID(ip, userAgent string) string {
+	sum := sha1.Sum([]byte(ip + "|" + userAgent))
+	return hex.EncodeToString(sum[:])
+}
+
+// RecordView 记录一次文章阅读，同一访客在去重窗口内只计一次
+func RecordView(articleID uint, visitor string) error {
+	dedupKey := fmt.Sprintf(viewDedupKeyTpl, articleID, visitor)
+	first, err := Redis.SetNX(ctx, dedupKey, 1, utils.ViewDedupWindow).Result()
+	if err != nil || !first {
+		return err
+	}
+
+	member := strconv.FormatUint(uint64(articleID), 10)
+	dailyKey := fmt.Sprintf(viewDailyKeyTpl, time.Now().Format(utils.TimeFormatDateV2))
+
+	pipe := Redis.TxPipeline()
+	pipe.HIncrBy(ctx, viewPendingKey, member, 1)
+	pipe.ZIncrBy(ctx, dailyKey, 1, member)
+	pipe.Expire(ctx, dailyKey, time.Duration(popularPeriods["month"]+1)*24*time.Hour)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// FlushViewCounts 将 Redis 中累计的阅读量增量写回数据库
+func FlushViewCounts() error {
+	// 先把待写回的增量改名，写回期间产生的新增量会落在新的 pending 中，不会丢失；
+	// 上次写回未完成时 flushing 仍然存在，先把它处理完
+	exists, err := Redis.Exists(ctx, viewFlushingKey).Result()
+	if err != nil {
+		return err
+	}
+	if exists == 0 {
+		pending, err := Redis.Exists(ctx, viewPendingKey).Result()
+		if err != nil || pending == 0 {
+			return err
+		}
+		if err := Redis.Rename(ctx, viewPendingKey, viewFlushingKey).Err(); err != nil {
+			return err
+		}
+	}
+
+	counts, err := Redis.HGetAll(ctx, viewFlushingKey).Result()
+	if err != nil {
+		return err
+	}
+
+	for member, value := range counts {
+		id, err1 := strconv.ParseUint(member, 10, 0)
+		n, err2 := strconv.ParseInt(value, 10, 64)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		err := db.Model(&Article{}).Where("id = ?", id).
+			UpdateColumn("view_count", gorm.Expr("view_count + ?", n)).Error
+		if err != nil {
+			return err
+		}
+		// 逐条删除已写回的字段，失败重试时不会重复累加
+		if err := Redis.HDel(ctx, viewFlushingKey, member).Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pendingViews 返回尚未写回数据库的阅读量增量
+func pendingViews(ids []uint) map[uint]int64 {
+	result := make(map[uint]int64, len(ids))
+	if len(ids) == 0 || Redis == nil {
+		return result
+	}
+
+	members := make([]string, 0, len(ids))
+	for _, id := range ids {
+		members = append(members, strconv.FormatUint(uint64(id), 10))
+	}
+	for _, key := range []string{viewPendingKey, viewFlushingKey} {
+		values, err := Redis.HMGet(ctx, key, members...).Result()
+		if err != nil {
+			continue
+		}
+		for i, v := range values {
+			if s, ok := v.(string); ok {
+				n, _ := strconv.ParseInt(s, 10, 64)
+				result[ids[i]] += n
+			}
+		}
+	}
+	return result
+}
+
+// fillViewCounts 将尚未写回数据库的阅读量加到文章的 ViewCount 上
+func fillViewCounts(articles []Article) {
+	ids := make([]uint, 0, len(articles))
+	for _, a := range articles {
+		ids = append(ids, a.ID)
+	}
+	pending := pendingViews(ids)
+	for i := range articles {
+		articles[i].ViewCount += pending[articles[i].ID]
+	}
+}
+
+// PopularArticle 是热门排行中的一篇文章及其统计周期内的阅读量
+type PopularArticle struct {
+	Article
+	PeriodViews int64 `json:"periodViews"`
+}
+
+// GetPopularArticles 按统计周期 (day/week/month) 内的阅读量返回热门文章
+func GetPopularArticles(period string, limit int) ([]PopularArticle, error) {
+	days, ok := popularPeriods[period]
+	if !ok {
+		return nil, errmsg.ErrInvalidPeriod
+	}
+
+	cacheKey := fmt.Sprintf(viewPopularTpl, period)
+	exists, err := Redis.Exists(ctx, cacheKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	if exists == 0 {
+		keys := make([]string, 0, days)
+		now := time.Now()
+		for i := 0; i < days; i++ {
+			keys = append(keys, fmt.Sprintf(viewDailyKeyTpl, now.AddDate(0, 0, -i).Format(utils.TimeFormatDateV2)))
+		}
+		pipe := Redis.TxPipeline()
+		pipe.ZUnionStore(ctx, cacheKey, &redis.ZStore{Keys: keys})
+		pipe.Expire(ctx, cacheKey, time.Minute)
+		if _, err := pipe.Exec(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	// 排行中可能有非公开文章和草稿，按批读取并过滤，直到凑够 limit 篇或排行读完
+	batch := int64(max(limit*2, popularBatchMin))
+	popular := make([]PopularArticle, 0, limit)
+	for start := int64(0); len(popular) < limit; start += batch {
+		ranked, err := Redis.ZRevRangeWithScores(ctx, cacheKey, start, start+batch-1).Result()
+		if err != nil {
+			return nil, err
+		}
+		if len(ranked) == 0 {
+			break
+		}
+		items, err := popularArticles(ranked)
+		if err != nil {
+			return nil, err
+		}
+		popular = append(popular, items[:min(len(items), limit-len(popular))]...)
+		if int64(len(ranked)) < batch {
+			break
+		}
+	}
+	return popular, nil
+}
+
+// popularArticles 查询排行中匿名访问者可见的文章，保持排行顺序
+func popularArticles(ranked []redis.Z) ([]PopularArticle, error) {
+	ids := make([]uint, 0, len(ranked))
+	scores := make(map[uint]int64, len(ranked))
+	for _, z := range ranked {
+		id, err := strconv.ParseUint(z.Member.(string), 10, 0)
+		if err == nil {
+			ids = append(ids, uint(id))
+			scores[uint(id)] = int64(z.Score)
+		}
+	}
+	var articles []Article
+	err := filterVisible(db.Preload("Category").Preload("Tags").Omit("content").Where("id IN ?", ids), Viewer{}).Find(&articles).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	fillViewCounts(articles)
+	byID := make(map[uint]Article, len(articles))
+	for _, a := range articles {
+		byID[a.ID] = a
+	}
+
+	popular := make([]PopularArticle, 0, len(articles))
+	for _, id := range ids {
+		if a, ok := byID[id]; ok {
+			popular = append(popular, PopularArticle{Article: a, PeriodViews: scores[id]})
+		}
+	}
+	return popular, nil
+}
@@ -0,0 +1,209 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"goblog/utils"
+	"goblog/utils/errmsg"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 文章的可见性
+const (
+	VisibilityPublic   = "public"   // 公开
+	VisibilityUnlisted = "unlisted" // 不出现在列表、订阅源和搜索中，知道链接即可访问
+	VisibilityPrivate  = "private"  // 仅登录用户可见
+	VisibilityRole     = "role"     // 仅指定角色的用户可见
+	VisibilityPassword = "password" // 出现在列表中，输入密码后才能查看正文
+)
+
+// RoleAdmin 是管理员角色，管理员可以查看所有文章
+const RoleAdmin = 1
+
+// UnlockTTL 是密码解锁凭证的有效期
+const UnlockTTL = 2 * time.Hour
+
+// Viewer 是当前访问者，匿名访问者的 UserID 为 0
+type Viewer struct {
+	UserID uint
+	Role   int
+}
+
+func (v Viewer) isAdmin() bool {
+	return v.UserID > 0 && v.Role == RoleAdmin
+}
+
+// filterVisible 只保留访问者可以在列表中看到的文章。
+// 草稿和不公开列出的文章只有管理员能在列表中看到；受密码保护的文章会出现在列表中，但正文会被 hideLocked 隐藏
+func filterVisible(DB *gorm.DB, v Viewer) *gorm.DB {
+	if v.isAdmin() {
+		return DB
+	}
+	DB = DB.Where("article.draft = ?", false)
+	if v.UserID == 0 {
+		return DB.Where("article.visibility IN ?", []string{VisibilityPublic, VisibilityPassword})
+	}
+	return DB.Where("article.visibility IN ? OR (article.visibility = ? AND article.visible_role = ?)",
+		[]string{VisibilityPublic, VisibilityPassword, VisibilityPrivate}, VisibilityRole, v.Role)
+}
+
+// filterPublic 只保留公开的文章，用于订阅源、站点地图等所有访问者共享的内容
+func filterPublic(DB *gorm.DB) *gorm.DB {
+	return DB.Where("article.visibility = ? AND article.draft = ?", VisibilityPublic, false)
+}
+
+// canList 与 filterVisible 的条件相同，用于内存中的过滤
+func (v Viewer) canList(visibility string, role int, draft bool) bool {
+	if draft {
+		return v.isAdmin()
+	}
+	switch visibility {
+	case VisibilityPublic, VisibilityPassword:
+		return true
+	case VisibilityPrivate:
+		return v.UserID > 0
+	case VisibilityRole:
+		return v.isAdmin() || (v.UserID > 0 && v.Role == role)
+	}
+	return v.isAdmin()
+}
+
+// hideLocked 隐藏列表中受密码保护文章的正文
+func hideLocked(articles []Article, v Viewer) {
+	if v.isAdmin() {
+		return
+	}
+	for i := range articles {
+		if articles[i].Visibility == VisibilityPassword {
+			articles[i].Content = ""
+			articles[i].Locked = true
+		}
+	}
+}
+
+// checkVisible 判断访问者能否查看文章详情，作者和管理员总是可以查看。unlock 是密码解锁凭证。
+// 草稿对其他访问者而言视为不存在
+func checkVisible(a *Article, v Viewer, unlock string) error {
+	if !a.Draft && (a.Visibility == VisibilityPublic || a.Visibility == VisibilityUnlisted) || v.isAdmin() {
+		return nil
+	}
+	if v.UserID > 0 {
+		isAuthor, err := isArticleAuthor(a.ID, v.UserID)
+		if err != nil || isAuthor {
+			return err
+		}
+	}
+	if a.Draft {
+		return errmsg.ErrArticleNotExist
+	}
+
+	switch a.Visibility {
+	case VisibilityPrivate:
+		if v.UserID == 0 {
+			return errmsg.ErrArticleNeedLogin
+		}
+	case VisibilityRole:
+		if v.UserID == 0 {
+			return errmsg.ErrArticleNeedLogin
+		}
+		if v.Role != a.VisibleRole {
+			return errmsg.ErrArticleForbidden
+		}
+	case VisibilityPassword:
+		if !checkUnlockToken(a, unlock) {
+			return errmsg.ErrArticleLocked
+		}
+	}
+	return nil
+}
+
+// isArticleAuthor 判断用户是否为文章作者
+func isArticleAuthor(articleID uint, userID uint) (bool, error) {
+	var count int64
+	err := db.Model(&UserArticle{}).Where("article_id = ? AND user_id = ?", articleID, userID).Count(&count).Error
+	return count > 0, err
+}
+
+// prepareVisibility 规范化可见性设置并对访问密码做哈希。
+// 编辑受密码保护的文章时未填写新密码则沿用原密码 current
+func prepareVisibility(a *Article, current string) error {
+	if a.Visibility == "" {
+		a.Visibility = VisibilityPublic
+	}
+	if a.Visibility != VisibilityRole {
+		a.VisibleRole = 0
+	}
+	if a.Visibility != VisibilityPassword {
+		a.Password = ""
+		return nil
+	}
+
+	if a.Password == "" {
+		if current == "" {
+			return errmsg.ErrArticlePwdEmpty
+		}
+		a.Password = current
+		return nil
+	}
+	hashed, err := HashPassword(a.Password)
+	if err != nil {
+		return err
+	}
+	a.Password = hashed
+	return nil
+}
+
+// UnlockArticle 校验文章的访问密码，成功时返回解锁凭证
+func UnlockArticle(id uint, password string) (string, error) {
+	var article Article
+	if err := db.Select("id", "visibility", "password", "draft").First(&article, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", errmsg.ErrArticleNotExist
+		}
+		return "", err
+	}
+	if article.Draft {
+		return "", errmsg.ErrArticleNotExist
+	}
+	if article.Visibility != VisibilityPassword {
+		return "", errmsg.ErrArticleNotLocked
+	}
+
+	ok, err := CheckPassword(article.Password, password)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", errmsg.ErrArticlePwdWrong
+	}
+	return unlockToken(&article, time.Now().Add(UnlockTTL)), nil
+}
+
+// unlockToken 生成 "过期时间.签名" 格式的解锁凭证。
+// 签名覆盖密码哈希，修改密码后已发放的凭证随之失效
+func unlockToken(a *Article, expires time.Time) string {
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(utils.JwtKey))
+	fmt.Fprintf(mac, "%d|%s|%s", a.ID, exp, a.Password)
+	return exp + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+// checkUnlockToken 校验解锁凭证的签名和有效期
+func checkUnlockToken(a *Article, token string) bool {
+	exp, _, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	unix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return false
+	}
+	return hmac.Equal([]byte(token), []byte(unlockToken(a, time.Unix(unix, 0))))
+}
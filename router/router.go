@@ -21,8 +21,14 @@ func InitRouter() {
 	frontRouter := gin.New()
 	frontRouter.Static("/assets", "./static/front/dist/assets")
 	frontRouter.StaticFile("/favicon.ico", "./static/front/dist/favicon.ico")
+	frontRouter.GET("/robots.txt", controller.GetRobots)              // robots.txt | 参数来源: 无
+	frontRouter.GET("/sitemap.xml", controller.GetSitemapIndex)       // 站点地图索引 | 参数来源: 无
+	frontRouter.GET("/sitemaps/:name", controller.GetSitemap)         // 文章/分类/标签站点地图 | 参数来源: URL 路径参数 (e.g., /sitemaps/articles-1.xml)
+	frontRouter.GET("/", controller.RenderHomePage)                   // 首页 (注入元信息) | 参数来源: 无
+	frontRouter.GET("/articles/:id", controller.RenderArticlePage)    // 文章页 (注入元信息) | 参数来源: URL 路径参数
+	frontRouter.GET("/categories/:id", controller.RenderCategoryPage) // 分类页 (注入元信息) | 参数来源: URL 路径参数
 	frontRouter.NoRoute(func(c *gin.Context) {
-		c.File("./static/front/dist/index.html")
+		c.File(controller.FrontIndexFile)
 	})
 
 	adminRouter := gin.New()
@@ -60,43 +66,110 @@ func registerApiRoutes(apiV1 *gin.RouterGroup) {
 		apiV1.POST("login/email", controller.LoginByEmail)    // 邮箱验证码登录 | 参数来源: JSON 请求体
 		apiV1.POST("email/code", controller.SendEmailForCode) // 发送邮箱验证码 | 参数来源: JSON 请求体
 		apiV1.GET("active", controller.ActiveEmail)           // 邮箱激活链接 | 参数来源: URL 查询参数 (e.g., /active?code=xxx)
+		apiV1.GET(".well-known/jwks.json", middleware.JWKS)   // JWT 公钥集合 (JWKS) | 参数来源: 无
 
 		// 分类模块
-		apiV1.GET("categories", controller.GetCategory)                 // 获取所有分类列表 | 参数来源: URL 查询参数 (e.g., /categories?pagesize=10)
-		apiV1.GET("categories/:id", controller.FindCategoryById)        // 获取单个分类信息 | 参数来源: URL 路径参数 (e.g., /categories/123)
-		apiV1.GET("categories/:id/articles", controller.GetCateArticle) // 获取某分类下的所有文章 | 参数来源: URL 路径参数 (+ 可选查询参数分页)
+		apiV1.GET("categories", controller.GetCategory)                   // 获取所有分类列表 | 参数来源: URL 查询参数 (e.g., /categories?pagesize=10)
+		apiV1.GET("categories/:id", controller.FindCategoryById)          // 获取单个分类信息 | 参数来源: URL 路径参数 (e.g., /categories/123)
+		apiV1.GET("categories/slug/:slug", controller.FindCategoryBySlug) // 通过 slug 获取分类信息 | 参数来源: URL 路径参数 (e.g., /categories/slug/shu-ju-ku)
 
 		// 文章模块
-		apiV1.GET("articles", controller.GetArticle)         // 获取文章列表 | 参数来源: URL 查询参数
-		apiV1.GET("articles/:id", controller.GetArticleInfo) // 获取单篇文章详情 | 参数来源: URL 路径参数
+		apiV1.GET("articles/popular", controller.GetPopularArticles)     // 获取热门文章排行 | 参数来源: URL 查询参数 (e.g., /articles/popular?period=week&limit=10)
+		apiV1.GET("articles/featured", controller.GetFeaturedArticles)   // 获取精选文章 (首页轮播) | 参数来源: URL 查询参数 (e.g., /articles/featured?limit=5)
+		apiV1.GET("articles/:id/related", controller.GetRelatedArticles) // 获取相关文章推荐 | 参数来源: URL 路径参数 + URL 查询参数 (e.g., /articles/3/related?limit=5)
+		apiV1.GET("tags", controller.GetTags)                            // 获取所有标签 | 参数来源: 无
 
-		// 评论模块
-		apiV1.GET("articles/:id/comments", controller.GetCommentsByArticleId) // 获取某文章下的所有评论 | 参数来源: URL 路径参数
+		// 归档模块
+		apiV1.GET("archives", controller.GetArchives) // 获取按年月分组的文章数量 | 参数来源: 无
+
+		// 系列模块
+		apiV1.GET("series", controller.GetSeriesList) // 获取系列列表 | 参数来源: URL 查询参数
+
+		// 订阅源模块 (format 可选 rss、atom、json)
+		apiV1.GET("feeds/:format", controller.GetSiteFeed)                    // 全站订阅源 | 参数来源: URL 路径参数 (e.g., /feeds/rss)
+		apiV1.GET("feeds/categories/:id/:format", controller.GetCategoryFeed) // 分类订阅源 | 参数来源: URL 路径参数 (e.g., /feeds/categories/3/atom)
+		apiV1.GET("feeds/tags/:name/:format", controller.GetTagFeed)          // 标签订阅源 | 参数来源: URL 路径参数 (e.g., /feeds/tags/golang/json)
+		apiV1.GET("feeds/authors/:id/:format", controller.GetAuthorFeed)      // 作者订阅源 | 参数来源: URL 路径参数 (e.g., /feeds/authors/1/rss)
+
+		// Markdown 渲染
+		apiV1.GET("markdown/highlight.css", controller.GetHighlightCSS) // 文章代码高亮样式表 | 参数来源: 无
+
+		// 写作客户端
+		apiV1.POST("xmlrpc", controller.XMLRPC) // MetaWeblog / Blogger XML-RPC 接口 (用户名 + 应用密码认证) | 参数来源: XML 请求体 (methodCall)
+	}
+
+	// --- 可选登录接口 (携带 Token 时识别用户，否则按匿名访客处理) ---
+	optionalV1 := apiV1.Group("", middleware.OptionalJwtToken())
+	{
+		// 文章模块 (按访问者的登录状态和角色过滤不可见的文章)
+		optionalV1.GET("articles", controller.GetArticle)                          // 获取文章列表 (置顶在前) | 参数来源: URL 查询参数 (e.g., /articles?sort=views，可选 newest、oldest、views、comments、updated)
+		optionalV1.GET("articles/:id", controller.GetArticleInfo)                  // 获取单篇文章详情 | 参数来源: URL 路径参数 (+ 解锁 cookie)
+		optionalV1.GET("articles/slug/:slug", controller.GetArticleBySlug)         // 通过 slug 获取文章详情 | 参数来源: URL 路径参数 (e.g., /articles/slug/go-yu-yan-ru-men)
+		optionalV1.POST("articles/:id/unlock", controller.UnlockArticle)           // 输入密码解锁文章，成功后下发签名 cookie | 参数来源: URL 路径参数 + JSON 请求体
+		optionalV1.GET("articles/:id/comments", controller.GetCommentsByArticleId) // 获取某文章下的所有评论 | 参数来源: URL 路径参数
+		optionalV1.GET("categories/:id/articles", controller.GetCateArticle)       // 获取某分类下的所有文章 | 参数来源: URL 路径参数 (+ 可选查询参数分页)
+		optionalV1.GET("archives/:year/:month", controller.GetArchiveArticles)     // 获取某年某月的文章 | 参数来源: URL 路径参数 + 可选查询参数分页 (e.g., /archives/2024/5?pagenum=1)
+		optionalV1.GET("search", controller.SearchArticles)                        // 全文搜索文章 | 参数来源: URL 查询参数 (e.g., /search?q=gin&cid=3)
+
+		// 系列模块 (目录按访问者过滤)
+		optionalV1.GET("series/:id", controller.GetSeries) // 获取系列详情及文章目录 | 参数来源: URL 路径参数
+
+		// 回应模块
+		optionalV1.POST("articles/:id/reactions", controller.AddArticleReaction)            // 点赞/表情回应文章 | 参数来源: URL 路径参数 + JSON 请求体
+		optionalV1.DELETE("articles/:id/reactions/:type", controller.DeleteArticleReaction) // 取消文章回应 | 参数来源: URL 路径参数
+		optionalV1.POST("comments/:id/reactions", controller.AddCommentReaction)            // 点赞/表情回应评论 | 参数来源: URL 路径参数 + JSON 请求体
+		optionalV1.DELETE("comments/:id/reactions/:type", controller.DeleteCommentReaction) // 取消评论回应 | 参数来源: URL 路径参数
+	}
+
+	// --- 写作客户端 (Micropub，Bearer Token 可在 Authorization 头、access_token 查询参数或表单字段中) ---
+	micropubV1 := apiV1.Group("micropub", middleware.AccessTokenParam(controller.MicropubMaxBody, controller.MicropubMaxMemory), middleware.JwtToken())
+	{
+		micropubV1.GET("", controller.GetMicropub)         // Micropub 查询 (q=config/source/syndicate-to/category) | 参数来源: URL 查询参数 (e.g., /micropub?q=source&url=...)
+		micropubV1.POST("", controller.Micropub)           // Micropub 创建/更新/删除/恢复文章 | 参数来源: 表单 (x-www-form-urlencoded 或 multipart/form-data) 或 JSON 请求体
+		micropubV1.POST("media", controller.MicropubMedia) // Micropub 媒体上传 | 参数来源: 表单 (multipart/form-data)
 	}
 
 	// --- 权限接口 (需要 JWT Token 验证) ---
 	apiV1.Use(middleware.JwtToken())
 	{
 		// 用户/个人模块
-		apiV1.GET("users", controller.GetUser)           // 获取用户列表 | 参数来源: URL 查询参数
-		apiV1.POST("users/add", controller.AddUser)      // 添加用户 | 参数来源: JSON 请求体
-		apiV1.GET("users/:id", controller.GetUserInfo)   // 获取指定用户详情 | 参数来源: URL 路径参数
-		apiV1.PUT("users/:id", controller.EditUser)      // 编辑指定用户信息 | 参数来源: URL 路径参数 + JSON 请求体
-		apiV1.DELETE("users/:id", controller.DeleteUser) // 删除指定用户 | 参数来源: URL 路径参数
+		apiV1.GET("users", controller.GetUser)                // 获取用户列表 | 参数来源: URL 查询参数
+		apiV1.POST("users/add", controller.AddUser)           // 添加用户 | 参数来源: JSON 请求体
+		apiV1.GET("users/:id", controller.GetUserInfo)        // 获取指定用户详情 | 参数来源: URL 路径参数
+		apiV1.PUT("users/:id", controller.EditUser)           // 编辑指定用户信息 | 参数来源: URL 路径参数 + JSON 请求体
+		apiV1.DELETE("users/:id", controller.DeleteUser)      // 删除指定用户 | 参数来源: URL 路径参数
+		apiV1.GET("users/:id/likes", controller.GetUserLikes) // 获取用户点赞过的文章 | 参数来源: URL 路径参数 + URL 查询参数
 
 		apiV1.GET("profile", controller.GetProfile)    // 获取当前登录用户的个人信息 | 参数来源: JWT Token
-		apiV1.PUT("profile", controller.UpdateProfile) // 更新当前登录用户的个人信息 | 参数来源: JSON 请求体
+		apiV1.PUT("profile", controller.UpdateProfile) // 更新当前登录用户的个人信息 | 参数来源: JSON 请求体 + If-Match 请求头 (或请求体中的 version)
+
+		apiV1.GET("profile/app-passwords", controller.GetAppPasswords)          // 获取当前用户的应用密码 (写作客户端使用) | 参数来源: JWT Token
+		apiV1.POST("profile/app-passwords", controller.AddAppPassword)          // 生成应用密码，明文只返回一次 | 参数来源: JSON 请求体
+		apiV1.DELETE("profile/app-passwords/:id", controller.DeleteAppPassword) // 撤销应用密码 | 参数来源: URL 路径参数
 
 		// 分类模块
 		apiV1.POST("categories", controller.AddCategory)          // 新增分类 | 参数来源: JSON 请求体
-		apiV1.PUT("categories/:id", controller.EditCategory)      // 编辑分类 | 参数来源: URL 路径参数 + JSON 请求体
+		apiV1.PUT("categories/:id", controller.EditCategory)      // 编辑分类 | 参数来源: URL 路径参数 + JSON 请求体 + If-Match 请求头 (或请求体中的 version)
 		apiV1.DELETE("categories/:id", controller.DeleteCategory) // 删除分类 | 参数来源: URL 路径参数
 
 		// 文章模块
 		apiV1.POST("articles", controller.AddArticle)          // 新增文章 | 参数来源: JSON 请求体
-		apiV1.PUT("articles/:id", controller.EditArticle)      // 编辑文章 | 参数来源: URL 路径参数 + JSON 请求体
+		apiV1.PUT("articles/:id", controller.EditArticle)      // 编辑文章 | 参数来源: URL 路径参数 + JSON 请求体 + If-Match 请求头 (或请求体中的 version)
 		apiV1.DELETE("articles/:id", controller.DeleteArticle) // 删除文章 | 参数来源: URL 路径参数
 
+		// 自动保存 (id 为 0 表示尚未发布的新文章)
+		apiV1.GET("articles/:id/autosave", controller.GetAutosave)        // 获取当前用户最新的自动保存 | 参数来源: URL 路径参数
+		apiV1.PUT("articles/:id/autosave", controller.SaveAutosave)       // 自动保存编辑器状态 | 参数来源: URL 路径参数 + JSON 请求体
+		apiV1.DELETE("articles/:id/autosave", controller.DiscardAutosave) // 丢弃自动保存 | 参数来源: URL 路径参数
+
+		// 系列模块
+		apiV1.POST("series", controller.AddSeries)                                     // 新增系列 | 参数来源: JSON 请求体
+		apiV1.PUT("series/:id", controller.EditSeries)                                 // 编辑系列 | 参数来源: URL 路径参数 + JSON 请求体
+		apiV1.DELETE("series/:id", controller.DeleteSeries)                            // 删除系列 (不删除文章) | 参数来源: URL 路径参数
+		apiV1.POST("series/:id/articles", controller.AddSeriesArticle)                 // 将文章追加到系列末尾 | 参数来源: URL 路径参数 + JSON 请求体
+		apiV1.PUT("series/:id/articles", controller.SetSeriesArticles)                 // 按顺序设置系列中的文章 (调整顺序) | 参数来源: URL 路径参数 + JSON 请求体
+		apiV1.DELETE("series/:id/articles/:articleId", controller.RemoveSeriesArticle) // 将文章移出系列 | 参数来源: URL 路径参数
+
 		// 评论模块
 		apiV1.POST("comments", controller.AddComment)          // 发表评论 | 参数来源: JSON 请求体
 		apiV1.DELETE("comments/:id", controller.DeleteComment) // 删除评论 | 参数来源: URL 路径参数
@@ -104,6 +177,36 @@ func registerApiRoutes(apiV1 *gin.RouterGroup) {
 		// 文件上传
 		apiV1.POST("upload", controller.Upload) // 上传文件 | 参数来源: 表单 (multipart/form-data)
 	}
+
+	// --- 管理员接口 (需要 JWT Token 且为管理员) ---
+	adminV1 := apiV1.Group("", middleware.AdminAuth())
+	{
+		// 邀请码模块
+		adminV1.GET("invitations", controller.GetInvitations)          // 获取邀请码列表 | 参数来源: URL 查询参数
+		adminV1.POST("invitations", controller.AddInvitation)          // 生成邀请码 | 参数来源: JSON 请求体
+		adminV1.DELETE("invitations/:id", controller.DeleteInvitation) // 作废邀请码 | 参数来源: URL 路径参数
+
+		// 内容安全模块
+		adminV1.GET("sanitize/reports", controller.GetSanitizeReports)          // 获取被清理器修改过的内容 | 参数来源: URL 查询参数 (e.g., /sanitize/reports?type=comment)
+		adminV1.DELETE("sanitize/reports/:id", controller.DeleteSanitizeReport) // 删除 (确认) 清理记录 | 参数来源: URL 路径参数
+
+		// 回收站模块 (kind 可选 articles、comments、users)
+		adminV1.GET("trash/:kind", controller.GetTrash)                  // 获取回收站内容 | 参数来源: URL 路径参数 + URL 查询参数 (e.g., /trash/articles?pagenum=1)
+		adminV1.POST("trash/:kind/:id/restore", controller.RestoreTrash) // 从回收站恢复 | 参数来源: URL 路径参数 (e.g., /trash/articles/3/restore)
+		adminV1.DELETE("trash/:kind/:id", controller.PurgeTrash)         // 彻底删除 | 参数来源: URL 路径参数
+
+		// 批量操作与审计日志
+		adminV1.POST("articles/bulk", controller.BulkArticles) // 批量操作文章 (同一事务，逐篇返回结果) | 参数来源: JSON 请求体 (e.g., {"op":"add_tags","ids":[1,2],"tags":["go"]})
+		adminV1.GET("audit-logs", controller.GetAuditLogs)     // 获取审计日志 | 参数来源: URL 查询参数 (e.g., /audit-logs?action=article.bulk.)
+
+		// 导入模块
+		adminV1.POST("import/markdown", controller.ImportMarkdown)   // 从 Markdown 压缩包导入文章 (默认试运行) | 参数来源: 表单 (multipart/form-data: file, dryRun, category)
+		adminV1.POST("import/wordpress", controller.ImportWordPress) // 从 WordPress WXR 文件导入文章、评论和作者 (默认试运行，可重复导入) | 参数来源: 表单 (multipart/form-data: file, dryRun, category)
+
+		// 导出模块
+		adminV1.GET("export", controller.ExportArchive)   // 导出全站内容为 ZIP 压缩包 (Markdown 文章及分类、标签、系列、评论 JSON) | 参数来源: URL 查询参数 (e.g., /export?media=true)
+		adminV1.GET("export/site", controller.ExportSite) // 将公开文章生成静态 HTML 站点并打包下载 | 参数来源: URL 查询参数 (e.g., /export/site?media=true)
+	}
 }
 
 // func InitRouter() {
@@ -66,16 +66,37 @@ func FromError(err error) *AppError {
 	UpdateUserSuccess    = NewAppError(http.StatusOK, 200, "用户信息更新成功")
 	DeleteUserSuccess    = NewAppError(http.StatusOK, 200, "删除用户成功")
 	UpdateProfileSuccess = NewAppError(http.StatusOK, 200, "个人信息更新成功")
+	CreateInviteSuccess  = NewAppError(http.StatusOK, 200, "邀请码创建成功")
+	DeleteInviteSuccess  = NewAppError(http.StatusOK, 200, "邀请码已作废")
+	CreateAppPwdSuccess  = NewAppError(http.StatusOK, 200, "应用密码创建成功，请立即保存，关闭后将无法再次查看")
+	DeleteAppPwdSuccess  = NewAppError(http.StatusOK, 200, "应用密码已撤销")
 
 	// 文章模块
 	CreateArticleSuccess = NewAppError(http.StatusOK, 200, "文章创建成功")
 	UpdateArticleSuccess = NewAppError(http.StatusOK, 200, "文章更新成功")
 	DeleteArticleSuccess = NewAppError(http.StatusOK, 200, "删除文章成功")
+	DeleteReportSuccess  = NewAppError(http.StatusOK, 200, "记录已删除")
+	UnlockArticleSuccess = NewAppError(http.StatusOK, 200, "文章已解锁")
+	AutosaveSuccess      = NewAppError(http.StatusOK, 200, "草稿已自动保存")
+	DiscardDraftSuccess  = NewAppError(http.StatusOK, 200, "草稿已丢弃")
 
 	// 评论模块
 	AddCommentSuccess    = NewAppError(http.StatusOK, 200, "评论添加成功")
 	DeleteCommentSuccess = NewAppError(http.StatusOK, 200, "删除评论成功")
 
+	// 回应模块
+	AddReactionSuccess    = NewAppError(http.StatusOK, 200, "回应成功")
+	DeleteReactionSuccess = NewAppError(http.StatusOK, 200, "已取消回应")
+
+	// 系列模块
+	CreateSeriesSuccess = NewAppError(http.StatusOK, 200, "系列创建成功")
+	UpdateSeriesSuccess = NewAppError(http.StatusOK, 200, "系列更新成功")
+	DeleteSeriesSuccess = NewAppError(http.StatusOK, 200, "删除系列成功")
+
+	// 回收站模块
+	RestoreTrashSuccess = NewAppError(http.StatusOK, 200, "恢复成功")
+	PurgeTrashSuccess   = NewAppError(http.StatusOK, 200, "已彻底删除")
+
 	// 分类模块
 	CreateCategorySuccess = NewAppError(http.StatusOK, 200, "分类创建成功")
 	UpdateCategorySuccess = NewAppError(http.StatusOK, 200, "分类更新成功")
@@ -91,25 +112,64 @@ func FromError(err error) *AppError {
 	ErrInternalServer = NewAppError(http.StatusInternalServerError, 500, "服务器内部错误")
 	ErrInvalidParams  = NewAppError(http.StatusBadRequest, 400, "请求参数无效")
 
+	// 乐观锁: 编辑时需通过 If-Match 请求头或请求体中的 version 指定编辑所基于的版本
+	ErrVersionRequired = NewAppError(http.StatusPreconditionRequired, 428, "请通过 If-Match 请求头或请求体中的 version 指定编辑所基于的版本")
+	ErrVersionMismatch = NewAppError(http.StatusPreconditionFailed, 412, "内容已被修改，If-Match 与当前版本不一致，请合并最新版本后重试")
+	ErrVersionConflict = NewAppError(http.StatusConflict, 409, "内容已被他人修改，请合并最新版本后重试")
+
 	ErrInvalidArticleID  = NewAppError(http.StatusBadRequest, 400, "无效的文章 ID")
 	ErrInvalidCategoryID = NewAppError(http.StatusBadRequest, 400, "无效的分类 ID")
 	ErrInvalidCommentID  = NewAppError(http.StatusBadRequest, 400, "无效的评论 ID")
 	ErrInvalidUserID     = NewAppError(http.StatusBadRequest, 400, "无效的用户 ID")
+	ErrInvalidInviteID   = NewAppError(http.StatusBadRequest, 400, "无效的邀请码 ID")
+	ErrInvalidReportID   = NewAppError(http.StatusBadRequest, 400, "无效的记录 ID")
+	ErrInvalidSeriesID   = NewAppError(http.StatusBadRequest, 400, "无效的系列 ID")
+	ErrInvalidTrashID    = NewAppError(http.StatusBadRequest, 400, "无效的回收站记录 ID")
+	ErrInvalidAppPwdID   = NewAppError(http.StatusBadRequest, 400, "无效的应用密码 ID")
 
 	// 用户模块错误 (1000...)
-	ErrUsernameUsed       = NewAppError(http.StatusBadRequest, 1001, "用户名已存在！")
-	ErrPasswordWrong      = NewAppError(http.StatusUnauthorized, 1002, "密码错误！")
-	ErrUserNotExist       = NewAppError(http.StatusNotFound, 1003, "用户不存在！")
-	ErrTokenNotExist      = NewAppError(http.StatusUnauthorized, 1004, "TOKEN不存在")
-	ErrTokenExpired       = NewAppError(http.StatusUnauthorized, 1005, "TOKEN已过期")
-	ErrTokenWrong         = NewAppError(http.StatusUnauthorized, 1006, "TOKEN不正确")
-	ErrTokenTypeWrong     = NewAppError(http.StatusUnauthorized, 1007, "TOKEN格式错误")
-	ErrNoAdminPermission  = NewAppError(http.StatusForbidden, 1008, "该用户无管理员权限")
-	ErrCreateSessionError = NewAppError(http.StatusInternalServerError, 1009, "创建会话失败，请稍后重试")
+	ErrUsernameUsed        = NewAppError(http.StatusBadRequest, 1001, "用户名已存在！")
+	ErrPasswordWrong       = NewAppError(http.StatusUnauthorized, 1002, "密码错误！")
+	ErrUserNotExist        = NewAppError(http.StatusNotFound, 1003, "用户不存在！")
+	ErrTokenNotExist       = NewAppError(http.StatusUnauthorized, 1004, "TOKEN不存在")
+	ErrTokenExpired        = NewAppError(http.StatusUnauthorized, 1005, "TOKEN已过期")
+	ErrTokenWrong          = NewAppError(http.StatusUnauthorized, 1006, "TOKEN不正确")
+	ErrTokenTypeWrong      = NewAppError(http.StatusUnauthorized, 1007, "TOKEN格式错误")
+	ErrNoAdminPermission   = NewAppError(http.StatusForbidden, 1008, "该用户无管理员权限")
+	ErrCreateSessionError  = NewAppError(http.StatusInternalServerError, 1009, "创建会话失败，请稍后重试")
+	ErrAppPasswordNotExist = NewAppError(http.StatusNotFound, 1010, "应用密码不存在")
+	ErrAppPasswordWrong    = NewAppError(http.StatusUnauthorized, 1011, "用户名或应用密码错误")
+
+	// 注册/邀请模块错误 (1100...)
+	ErrRegisterClosed        = NewAppError(http.StatusForbidden, 1101, "当前站点已关闭注册")
+	ErrInviteRequired        = NewAppError(http.StatusForbidden, 1102, "当前站点仅允许通过邀请码注册")
+	ErrInviteNotExist        = NewAppError(http.StatusBadRequest, 1103, "邀请码不存在")
+	ErrInviteExpired         = NewAppError(http.StatusBadRequest, 1104, "邀请码已过期")
+	ErrInviteUsedUp          = NewAppError(http.StatusBadRequest, 1105, "邀请码使用次数已达上限")
+	ErrEmailDomainNotAllowed = NewAppError(http.StatusForbidden, 1106, "该邮箱域名不允许注册")
 
 	// 文章模块错误 (2000...)
-	ErrArticleNotExist  = NewAppError(http.StatusNotFound, 2001, "文章不存在!")
-	ErrArticleNoComment = NewAppError(http.StatusOK, 2002, "该文章没有评论") // 注意：没有评论通常不是一个错误，返回200 OK
+	ErrArticleNotExist    = NewAppError(http.StatusNotFound, 2001, "文章不存在!")
+	ErrArticleNoComment   = NewAppError(http.StatusOK, 2002, "该文章没有评论") // 注意：没有评论通常不是一个错误，返回200 OK
+	ErrInvalidPeriod      = NewAppError(http.StatusBadRequest, 2003, "统计周期无效，可选 day、week、month")
+	ErrCommentNotExist    = NewAppError(http.StatusNotFound, 2004, "评论不存在!")
+	ErrSlugInvalid        = NewAppError(http.StatusBadRequest, 2005, "slug 只能包含字母、数字和连字符")
+	ErrSlugUsed           = NewAppError(http.StatusBadRequest, 2006, "该 slug 已被使用")
+	ErrSlugNotExist       = NewAppError(http.StatusNotFound, 2007, "链接不存在!")
+	ErrFeedFormat         = NewAppError(http.StatusBadRequest, 2008, "订阅格式无效，可选 rss、atom、json")
+	ErrTagNotExist        = NewAppError(http.StatusNotFound, 2009, "标签不存在!")
+	ErrSitemapNotExist    = NewAppError(http.StatusNotFound, 2010, "站点地图不存在!")
+	ErrReportNotExist     = NewAppError(http.StatusNotFound, 2011, "清理记录不存在!")
+	ErrInvalidArchive     = NewAppError(http.StatusBadRequest, 2012, "归档年月无效")
+	ErrArticleNeedLogin   = NewAppError(http.StatusUnauthorized, 2013, "该文章仅登录用户可见")
+	ErrArticleForbidden   = NewAppError(http.StatusForbidden, 2014, "无权查看该文章")
+	ErrArticleLocked      = NewAppError(http.StatusForbidden, 2015, "该文章受密码保护，请先输入密码")
+	ErrArticlePwdWrong    = NewAppError(http.StatusForbidden, 2016, "文章密码错误")
+	ErrArticlePwdEmpty    = NewAppError(http.StatusBadRequest, 2017, "受密码保护的文章必须设置访问密码")
+	ErrArticleNotLocked   = NewAppError(http.StatusBadRequest, 2018, "该文章没有设置访问密码")
+	ErrAutosaveNotExist   = NewAppError(http.StatusNotFound, 2019, "没有自动保存的草稿")
+	ErrBulkOp             = NewAppError(http.StatusBadRequest, 2020, "批量操作类型无效")
+	ErrClientPostCategory = NewAppError(http.StatusBadRequest, 2021, "请至少为文章选择一个分类")
 
 	// 分类模块错误 (3000...)
 	ErrCateNameUsed = NewAppError(http.StatusBadRequest, 3001, "该分类已存在！")
@@ -123,5 +183,23 @@ func FromError(err error) *AppError {
 	ErrCodeWrong         = NewAppError(http.StatusBadRequest, 4005, "验证码错误")
 	ErrEmailUsed         = NewAppError(http.StatusBadRequest, 4006, "邮箱已使用")
 
+	// 回应模块错误 (5000...)
+	ErrReactionInvalid   = NewAppError(http.StatusBadRequest, 5001, "不支持的回应类型")
+	ErrReactionNeedLogin = NewAppError(http.StatusUnauthorized, 5002, "匿名用户只能点赞，请登录后再使用表情回应")
+
+	// 系列模块错误 (6000...)
+	ErrSeriesNotExist     = NewAppError(http.StatusNotFound, 6001, "系列不存在!")
+	ErrSeriesArticleUsed  = NewAppError(http.StatusBadRequest, 6002, "文章已属于某个系列")
+	ErrSeriesArticleNotIn = NewAppError(http.StatusNotFound, 6003, "文章不在该系列中")
+
+	// 回收站模块错误 (7000...)
+	ErrTrashKind     = NewAppError(http.StatusBadRequest, 7001, "回收站类型无效，可选 articles、comments、users")
+	ErrTrashNotExist = NewAppError(http.StatusNotFound, 7002, "回收站中没有该记录")
+
+	// 导入模块错误 (8000...)
+	ErrImportFile     = NewAppError(http.StatusBadRequest, 8001, "导入文件无效，请上传 ZIP 压缩包")
+	ErrImportTooLarge = NewAppError(http.StatusRequestEntityTooLarge, 8002, "导入文件过大")
+	ErrImportEmpty    = NewAppError(http.StatusBadRequest, 8003, "压缩包中没有 Markdown 文件")
+
 	ErrGetFileFailed = NewAppError(http.StatusBadRequest, 400, "无法获取上传文件，请确保请求中包含名为 'file' 的文件字段")
 )
@@ -0,0 +1,296 @@
+// Package frontmatter 解析 Hexo、Hugo、Jekyll 等静态博客 Markdown 文件开头的 YAML (---) 或 TOML (+++) 元信息
+package frontmatter
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Meta 是从元信息中提取出的文章属性，各静态博客的字段名差异已被统一
+type Meta struct {
+	Title       string
+	Slug        string
+	Date        time.Time
+	Description string
+	Image       string
+	Categories  []string
+	Tags        []string
+	Draft       bool
+	Visibility  string // 本站导出的文章可见性，其他静态博客没有该字段
+	VisibleRole int    // 可见性为 role 时允许查看的角色
+}
+
+// dateLayouts 是常见的日期格式，依次尝试
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// Parse 拆分元信息和正文。没有元信息时 Meta 为零值，正文为全文
+func Parse(src []byte) (Meta, []byte, error) {
+	src = bytes.TrimPrefix(src, []byte("\xef\xbb\xbf"))
+	src = bytes.ReplaceAll(src, []byte("\r\n"), []byte("\n"))
+
+	var fields map[string]any
+	var body []byte
+	switch {
+	case bytes.HasPrefix(src, []byte("---\n")):
+		head, rest, ok := split(src, "---")
+		if !ok {
+			return Meta{}, src, nil
+		}
+		if err := yaml.Unmarshal(head, &fields); err != nil {
+			return Meta{}, nil, fmt.Errorf("YAML 元信息格式错误: %w", err)
+		}
+		body = rest
+	case bytes.HasPrefix(src, []byte("+++\n")):
+		head, rest, ok := split(src, "+++")
+		if !ok {
+			return Meta{}, src, nil
+		}
+		if err := toml.Unmarshal(head, &fields); err != nil {
+			return Meta{}, nil, fmt.Errorf("TOML 元信息格式错误: %w", err)
+		}
+		body = rest
+	default:
+		return Meta{}, src, nil
+	}
+
+	return newMeta(fields), bytes.TrimLeft(body, "\n"), nil
+}
+
+// split 按分隔行拆分元信息和正文，src 以分隔行开头
+func split(src []byte, delim string) ([]byte, []byte, bool) {
+	rest := src[len(delim)+1:]
+	if bytes.HasPrefix(rest, []byte(delim+"\n")) || bytes.Equal(rest, []byte(delim)) {
+		return nil, rest[min(len(delim)+1, len(rest)):], true
+	}
+	end := bytes.Index(rest, []byte("\n"+delim+"\n"))
+	if end < 0 {
+		if !bytes.HasSuffix(rest, []byte("\n"+delim)) {
+			return nil, nil, false
+		}
+		return rest[:len(rest)-len(delim)-1], nil, true
+	}
+	return rest[:end], rest[end+len(delim)+2:], true
+}
+
+// newMeta 从元信息字段中提取文章属性，字段名不区分大小写
+func newMeta(fields map[string]any) Meta {
+	lower := make(map[string]any, len(fields))
+	for k, v := range fields {
+		lower[strings.ToLower(k)] = v
+	}
+
+	var m Meta
+	m.Title = first(lower, "title")
+	m.Slug = first(lower, "slug")
+	if m.Slug == "" {
+		m.Slug = lastSegment(first(lower, "permalink", "url"))
+	}
+	m.Description = first(lower, "description", "excerpt", "summary", "subtitle")
+	m.Image = first(lower, "cover", "image", "thumbnail", "banner", "featured_image")
+	m.Date = parseDate(lower["date"])
+	if m.Date.IsZero() {
+		m.Date = parseDate(lower["publishdate"])
+	}
+	m.Categories = list(lower["categories"])
+	if len(m.Categories) == 0 {
+		m.Categories = list(lower["category"])
+	}
+	m.Tags = list(lower["tags"])
+	if len(m.Tags) == 0 {
+		m.Tags = list(lower["keywords"])
+	}
+
+	// Hugo 使用 draft: true，Hexo 和 Jekyll 使用 published: false
+	if draft, ok := lower["draft"].(bool); ok {
+		m.Draft = draft
+	}
+	if published, ok := lower["published"].(bool); ok && !published {
+		m.Draft = true
+	}
+
+	m.Visibility = strings.ToLower(first(lower, "visibility"))
+	m.VisibleRole, _ = strconv.Atoi(first(lower, "visible_role"))
+	return m
+}
+
+// first 返回第一个非空的字符串字段
+func first(fields map[string]any, keys ...string) string {
+	for _, key := range keys {
+		if s := scalar(fields[key]); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// scalar 将标量字段转换为字符串
+func scalar(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case []any, map[string]any:
+		return ""
+	default:
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+}
+
+// list 将分类、标签字段转换为字符串列表。
+// 支持列表、逗号分隔的字符串和 Jekyll 的空格分隔字符串；Hexo 的多级分类 ([父, 子]) 展开为各级名称
+func list(v any) []string {
+	var items []string
+	switch v := v.(type) {
+	case string:
+		sep := ","
+		if !strings.Contains(v, ",") {
+			sep = " "
+		}
+		items = strings.Split(v, sep)
+	case []any:
+		for _, item := range v {
+			if nested, ok := item.([]any); ok {
+				items = append(items, list(nested)...)
+				continue
+			}
+			items = append(items, scalar(item))
+		}
+	}
+
+	seen := make(map[string]bool, len(items))
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" || seen[item] {
+			continue
+		}
+		seen[item] = true
+		result = append(result, item)
+	}
+	return result
+}
+
+// localTime 是 TOML 的本地日期和本地日期时间 (不带时区)
+type localTime interface {
+	AsTime(zone *time.Location) time.Time
+}
+
+// parseDate 解析日期字段。YAML 和 TOML 的日期可能已被解码为时间类型，也可能是字符串
+func parseDate(v any) time.Time {
+	switch v := v.(type) {
+	case time.Time:
+		// YAML 将不带时区的时间解码为 UTC，静态博客中这类时间按本地时间书写
+		if v.Location() == time.UTC {
+			return time.Date(v.Year(), v.Month(), v.Day(), v.Hour(), v.Minute(), v.Second(), v.Nanosecond(), time.Local)
+		}
+		return v
+	case localTime:
+		return v.AsTime(time.Local)
+	case string:
+		v = strings.TrimSpace(v)
+		for _, layout := range dateLayouts {
+			if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// lastSegment 返回路径的最后一段，用于从 permalink 中提取 slug
+func lastSegment(path string) string {
+	path = strings.Trim(path, "/")
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		path = path[i+1:]
+	}
+	if strings.Contains(path, ":") { // 未展开的占位符，如 :title
+		return ""
+	}
+	return strings.TrimSuffix(path, ".html")
+}
+
+// Format 生成带 YAML 元信息的 Markdown 文件，可被 Parse 和常见静态博客读取。
+// extra 中的字段按键名排序追加在标准字段之后，值为零值的字段不输出
+func Format(m Meta, extra map[string]any, body []byte) ([]byte, error) {
+	head := &yaml.Node{Kind: yaml.MappingNode}
+	add := func(key string, value any) error {
+		var v yaml.Node
+		if err := v.Encode(value); err != nil {
+			return fmt.Errorf("元信息字段 %s: %w", key, err)
+		}
+		head.Content = append(head.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &v)
+		return nil
+	}
+
+	fields := []struct {
+		key   string
+		value any
+		empty bool
+	}{
+		{"title", m.Title, false},
+		{"slug", m.Slug, m.Slug == ""},
+		{"date", m.Date, m.Date.IsZero()},
+		{"description", m.Description, m.Description == ""},
+		{"image", m.Image, m.Image == ""},
+		{"categories", m.Categories, len(m.Categories) == 0},
+		{"tags", m.Tags, len(m.Tags) == 0},
+		{"draft", m.Draft, !m.Draft},
+		{"visibility", m.Visibility, m.Visibility == ""},
+		{"visible_role", m.VisibleRole, m.VisibleRole == 0},
+	}
+	for _, f := range fields {
+		if f.empty {
+			continue
+		}
+		if err := add(f.key, f.value); err != nil {
+			return nil, err
+		}
+	}
+
+	keys := make([]string, 0, len(extra))
+	for key, value := range extra {
+		if value != nil && !reflect.ValueOf(value).IsZero() {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := add(key, extra[key]); err != nil {
+			return nil, err
+		}
+	}
+
+	var b bytes.Buffer
+	b.WriteString("---\n")
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(head); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	b.WriteString("---\n\n")
+	b.Write(bytes.TrimLeft(body, "\n"))
+	if len(body) > 0 && body[len(body)-1] != '\n' {
+		b.WriteByte('\n')
+	}
+	return b.Bytes(), nil
+}
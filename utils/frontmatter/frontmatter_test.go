@@ -0,0 +1,253 @@
+package frontmatter
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		meta Meta
+		body string
+	}{
+		{
+			name: "没有元信息",
+			src:  "# 标题\n\n正文\n",
+			body: "# 标题\n\n正文\n",
+		},
+		{
+			name: "Hexo YAML",
+			src:  "\xef\xbb\xbf---\r\ntitle: Hello World\r\ndate: 2024-01-02 03:04:05\r\ncategories:\r\n  - [编程, Go]\r\n  - 笔记\r\ntags: [go, test, go]\r\nexcerpt: 摘要\r\ncover: /img/a.png\r\n---\r\n\r\n正文\r\n",
+			meta: Meta{
+				Title:       "Hello World",
+				Date:        time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local),
+				Description: "摘要",
+				Image:       "/img/a.png",
+				Categories:  []string{"编程", "Go", "笔记"},
+				Tags:        []string{"go", "test"},
+			},
+			body: "正文\n",
+		},
+		{
+			name: "Jekyll YAML",
+			src:  "---\nTitle: Post\npermalink: /2024/01/my-post.html\ncategory: 随笔\ntags: a b  c\npublished: false\n---\n正文",
+			meta: Meta{
+				Title:      "Post",
+				Slug:       "my-post",
+				Categories: []string{"随笔"},
+				Tags:       []string{"a", "b", "c"},
+				Draft:      true,
+			},
+			body: "正文",
+		},
+		{
+			name: "Hugo TOML",
+			src:  "+++\ntitle = \"Hugo\"\nslug = \"hugo-post\"\ndate = 2024-01-02T03:04:05+08:00\ndescription = \"描述\"\ncategories = [\"技术\"]\nkeywords = \"x, y\"\ndraft = true\n+++\n\n正文\n",
+			meta: Meta{
+				Title:       "Hugo",
+				Slug:        "hugo-post",
+				Date:        time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 8*3600)),
+				Description: "描述",
+				Categories:  []string{"技术"},
+				Tags:        []string{"x", "y"},
+				Draft:       true,
+			},
+			body: "正文\n",
+		},
+		{
+			name: "TOML 本地日期",
+			src:  "+++\ntitle = \"Local\"\npublishDate = 2024-01-02\n+++\n",
+			meta: Meta{
+				Title: "Local",
+				Date:  time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local),
+			},
+		},
+		{
+			name: "未展开的 permalink 占位符",
+			src:  "---\ntitle: T\npermalink: /posts/:title/\n---\n",
+			meta: Meta{Title: "T"},
+		},
+		{
+			name: "可见性",
+			src:  "---\ntitle: T\nvisibility: Role\nvisible_role: 2\n---\n",
+			meta: Meta{Title: "T", Visibility: "role", VisibleRole: 2},
+		},
+		{
+			name: "空的元信息",
+			src:  "---\n---\n正文\n",
+			body: "正文\n",
+		},
+		{
+			name: "只有元信息",
+			src:  "---\ntitle: T\n---",
+			meta: Meta{Title: "T"},
+		},
+		{
+			name: "未闭合的元信息按正文处理",
+			src:  "---\ntitle: T\n正文\n",
+			body: "---\ntitle: T\n正文\n",
+		},
+		{
+			name: "正文中的分隔线",
+			src:  "---\ntitle: T\n---\n第一段\n\n---\n\n第二段\n",
+			meta: Meta{Title: "T"},
+			body: "第一段\n\n---\n\n第二段\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta, body, err := Parse([]byte(tt.src))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !meta.Date.Equal(tt.meta.Date) {
+				t.Errorf("Date = %v, want %v", meta.Date, tt.meta.Date)
+			}
+			meta.Date, tt.meta.Date = time.Time{}, time.Time{}
+			if len(meta.Categories) == 0 {
+				meta.Categories = nil
+			}
+			if len(meta.Tags) == 0 {
+				meta.Tags = nil
+			}
+			if !reflect.DeepEqual(meta, tt.meta) {
+				t.Errorf("Meta = %+v, want %+v", meta, tt.meta)
+			}
+			if string(body) != tt.body {
+				t.Errorf("body = %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"YAML", "---\ntitle: [a\n---\n"},
+		{"TOML", "+++\ntitle = \n+++\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := Parse([]byte(tt.src)); err == nil {
+				t.Error("Parse() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestParseDateLayouts(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2024-01-02T03:04:05+08:00", time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 8*3600))},
+		{"2024-01-02T03:04:05", time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)},
+		{"2024-01-02 03:04:05 +0800", time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 8*3600))},
+		{"2024-01-02 03:04:05 +08:00", time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 8*3600))},
+		{"2024-01-02 03:04:05", time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local)},
+		{"2024-01-02 03:04", time.Date(2024, 1, 2, 3, 4, 0, 0, time.Local)},
+		{" 2024-01-02 ", time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local)},
+		{"2024/01/02", time.Time{}},
+		{"", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			// 用引号强制按字符串解析，避免 YAML 自行解码时间
+			meta, _, err := Parse([]byte("---\ndate: \"" + tt.value + "\"\n---\n"))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !meta.Date.Equal(tt.want) {
+				t.Errorf("Date = %v, want %v", meta.Date, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name  string
+		meta  Meta
+		extra map[string]any
+		body  string
+		want  string
+	}{
+		{
+			name: "只有标题",
+			meta: Meta{Title: "T"},
+			body: "\n\n正文",
+			want: "---\ntitle: T\n---\n\n正文\n",
+		},
+		{
+			name: "全部字段",
+			meta: Meta{
+				Title:       "标题: 冒号",
+				Slug:        "slug",
+				Date:        time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+				Description: "描述",
+				Image:       "/a.png",
+				Categories:  []string{"分类"},
+				Tags:        []string{"a", "b"},
+				Draft:       true,
+				Visibility:  "role",
+				VisibleRole: 2,
+			},
+			extra: map[string]any{"views": 10, "author": "admin", "empty": ""},
+			body:  "正文\n",
+			want: "---\ntitle: '标题: 冒号'\nslug: slug\ndate: 2024-01-02T03:04:05Z\ndescription: 描述\nimage: /a.png\n" +
+				"categories:\n  - 分类\ntags:\n  - a\n  - b\ndraft: true\nvisibility: role\nvisible_role: 2\n" +
+				"author: admin\nviews: 10\n---\n\n正文\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Format(tt.meta, tt.extra, []byte(tt.body))
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	want := Meta{
+		Title:       "标题",
+		Slug:        "round-trip",
+		Date:        time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 8*3600)),
+		Description: "描述",
+		Categories:  []string{"分类"},
+		Tags:        []string{"a", "b"},
+		Draft:       true,
+		Visibility:  "password",
+	}
+	src, err := Format(want, nil, []byte("正文\n"))
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	got, body, err := Parse(src)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !got.Date.Equal(want.Date) {
+		t.Errorf("Date = %v, want %v", got.Date, want.Date)
+	}
+	got.Date, want.Date = time.Time{}, time.Time{}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Meta = %+v, want %+v", got, want)
+	}
+	if string(body) != "正文\n" {
+		t.Errorf("body = %q", body)
+	}
+}
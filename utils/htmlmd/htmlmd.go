@@ -0,0 +1,260 @@
+// Package htmlmd 将 HTML 正文转换为 Markdown，用于导入 WordPress 等以 HTML 保存正文的博客。
+// 无法用 Markdown 表示的元素 (表格、视频、内嵌框架等) 保留为 HTML，由保存时的清理器处理
+package htmlmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	blankLines  = regexp.MustCompile(`\n{3,}`)
+	blankSpaces = regexp.MustCompile(`(?m)^[ \t]+$`)
+	innerSpaces = regexp.MustCompile(`([^ \n]) {2,}`)
+	hardBreak   = regexp.MustCompile(`\\\n[ \t]+`)
+	spaces      = regexp.MustCompile(`[ \t\r\n\f]+`)
+	escaper     = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`)
+	langClass   = regexp.MustCompile(`(?:language-|lang-|lang:|brush:\s*)([\w+#-]+)`)
+	placeholder = regexp.MustCompile("\x00(\\d+)\x00")
+	nestedList  = regexp.MustCompile(`\n\n((?:- |\d+\. ))`)
+)
+
+// converter 保存转换过程中的代码块，代码块先以占位符输出，最后再替换回去，避免被空白整理破坏
+type converter struct {
+	codes []string
+}
+
+// Convert 将 HTML 片段转换为 Markdown
+func Convert(src string) (string, error) {
+	nodes, err := html.ParseFragment(strings.NewReader(src), &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body})
+	if err != nil {
+		return "", err
+	}
+
+	c := &converter{}
+	var b strings.Builder
+	for _, n := range nodes {
+		b.WriteString(c.render(n))
+	}
+	md := blankSpaces.ReplaceAllString(b.String(), "")
+	md = innerSpaces.ReplaceAllString(md, "$1 ")
+	md = blankLines.ReplaceAllString(strings.TrimSpace(md), "\n\n")
+	md = placeholder.ReplaceAllStringFunc(md, func(s string) string {
+		var i int
+		fmt.Sscanf(strings.Trim(s, "\x00"), "%d", &i)
+		return c.codes[i]
+	})
+	return md + "\n", nil
+}
+
+// children 依次转换子节点
+func (c *converter) children(n *html.Node) string {
+	var b strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		b.WriteString(c.render(child))
+	}
+	return b.String()
+}
+
+// block 转换块级元素的内容，前后各空一行
+func (c *converter) block(n *html.Node) string {
+	content := strings.TrimSpace(hardBreak.ReplaceAllString(c.children(n), "\\\n"))
+	if content == "" {
+		return ""
+	}
+	return "\n\n" + content + "\n\n"
+}
+
+func (c *converter) render(n *html.Node) string {
+	switch n.Type {
+	case html.TextNode:
+		return escaper.Replace(spaces.ReplaceAllString(n.Data, " "))
+	case html.ElementNode:
+	default:
+		return ""
+	}
+
+	switch n.DataAtom {
+	case atom.P, atom.Div, atom.Section, atom.Article, atom.Header, atom.Footer, atom.Figure, atom.Figcaption, atom.Center:
+		return c.block(n)
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(n.Data[1] - '0')
+		text := strings.TrimSpace(spaces.ReplaceAllString(c.children(n), " "))
+		if text == "" {
+			return ""
+		}
+		return "\n\n" + strings.Repeat("#", level) + " " + text + "\n\n"
+	case atom.Br:
+		return "\\\n"
+	case atom.Hr:
+		return "\n\n---\n\n"
+	case atom.Strong, atom.B:
+		return wrap(c.children(n), "**")
+	case atom.Em, atom.I:
+		return wrap(c.children(n), "*")
+	case atom.Del, atom.S, atom.Strike:
+		return wrap(c.children(n), "~~")
+	case atom.Code, atom.Kbd, atom.Tt:
+		return inlineCode(textContent(n))
+	case atom.Pre:
+		return c.pre(n)
+	case atom.A:
+		text := c.children(n)
+		href := attr(n, "href")
+		if href == "" || strings.TrimSpace(text) == "" {
+			return text
+		}
+		if title := attr(n, "title"); title != "" {
+			return fmt.Sprintf("[%s](%s %q)", strings.TrimSpace(text), href, title)
+		}
+		return fmt.Sprintf("[%s](%s)", strings.TrimSpace(text), href)
+	case atom.Img:
+		src := attr(n, "src")
+		if src == "" {
+			return ""
+		}
+		return fmt.Sprintf("![%s](%s)", escaper.Replace(attr(n, "alt")), src)
+	case atom.Ul, atom.Ol:
+		return c.list(n)
+	case atom.Blockquote:
+		content := strings.TrimSpace(c.block(n))
+		if content == "" {
+			return ""
+		}
+		content = blankLines.ReplaceAllString(blankSpaces.ReplaceAllString(content, ""), "\n\n")
+		lines := strings.Split(content, "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		return "\n\n" + strings.Join(lines, "\n") + "\n\n"
+	case atom.Script, atom.Style, atom.Noscript, atom.Head, atom.Title:
+		return ""
+	case atom.Table, atom.Iframe, atom.Video, atom.Audio, atom.Embed, atom.Object:
+		return "\n\n" + c.raw(n) + "\n\n"
+	}
+	return c.children(n)
+}
+
+// pre 将预格式化文本转换为围栏代码块，语言取自 pre 或 code 的 class (如 language-go、brush: php)
+func (c *converter) pre(n *html.Node) string {
+	code := strings.Trim(textContent(n), "\n")
+	lang := languageOf(n)
+	if inner := n.FirstChild; lang == "" && inner != nil && inner.DataAtom == atom.Code {
+		lang = languageOf(inner)
+	}
+
+	fence := "```"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	c.codes = append(c.codes, fence+lang+"\n"+code+"\n"+fence)
+	return fmt.Sprintf("\n\n\x00%d\x00\n\n", len(c.codes)-1)
+}
+
+// list 转换有序和无序列表，嵌套列表按标记宽度缩进
+func (c *converter) list(n *html.Node) string {
+	var b strings.Builder
+	index := 1
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode || li.DataAtom != atom.Li {
+			continue
+		}
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = fmt.Sprintf("%d. ", index)
+			index++
+		}
+		content := strings.TrimSpace(hardBreak.ReplaceAllString(c.children(li), "\\\n"))
+		content = blankLines.ReplaceAllString(content, "\n\n")
+		// 子列表紧跟在列表项的文字之后，中间空行会使列表变为松散列表 (每项包裹在 <p> 中)
+		content = nestedList.ReplaceAllString(content, "\n$1")
+		indent := strings.Repeat(" ", len(marker))
+		lines := strings.Split(content, "\n")
+		for i := 1; i < len(lines); i++ {
+			if lines[i] != "" {
+				lines[i] = indent + lines[i]
+			}
+		}
+		b.WriteString(marker + strings.Join(lines, "\n") + "\n")
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "\n\n" + b.String() + "\n"
+}
+
+// raw 将元素原样输出为 HTML
+func (c *converter) raw(n *html.Node) string {
+	var b strings.Builder
+	if err := html.Render(&b, n); err != nil {
+		return ""
+	}
+	c.codes = append(c.codes, b.String())
+	return fmt.Sprintf("\x00%d\x00", len(c.codes)-1)
+}
+
+// wrap 用强调标记包裹文本，标记紧贴文字，两侧的空白移到标记之外
+func wrap(text string, mark string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	lead := text[:len(text)-len(strings.TrimLeft(text, " "))]
+	trail := text[len(strings.TrimRight(text, " ")):]
+	return lead + mark + trimmed + mark + trail
+}
+
+// inlineCode 生成行内代码，内容包含反引号时使用更长的定界符
+func inlineCode(code string) string {
+	if code == "" {
+		return ""
+	}
+	fence := "`"
+	for strings.Contains(code, fence) {
+		fence += "`"
+	}
+	if strings.HasPrefix(code, "`") || strings.HasSuffix(code, "`") {
+		return fence + " " + code + " " + fence
+	}
+	return fence + code + fence
+}
+
+// textContent 返回元素内的全部文本，<br> 转换为换行
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		switch {
+		case n.Type == html.TextNode:
+			b.WriteString(n.Data)
+		case n.Type == html.ElementNode && n.DataAtom == atom.Br:
+			b.WriteByte('\n')
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+// languageOf 从 class 中提取代码语言
+func languageOf(n *html.Node) string {
+	if match := langClass.FindStringSubmatch(attr(n, "class")); match != nil {
+		return strings.ToLower(match[1])
+	}
+	return ""
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return strings.TrimSpace(a.Val)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,129 @@
+package htmlmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer/html"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"空内容", "", "\n"},
+		{"段落", "<p>第一段</p>\n<p>第二段</p>", "第一段\n\n第二段\n"},
+		{"标题", "<h1>一级</h1><h3> 三级 <em>强调</em> </h3>", "# 一级\n\n### 三级 *强调*\n"},
+		{"空标题", "<h2> </h2><p>正文</p>", "正文\n"},
+		{"强调", "<p>a <strong>粗体 </strong>b <em>斜体</em> <del>删除</del></p>", "a **粗体** b *斜体* ~~删除~~\n"},
+		{"转义", "<p>1*2_3 [x] `y` a\\b</p>", "1\\*2\\_3 \\[x\\] \\`y\\` a\\\\b\n"},
+		{"换行", "<p>第一行<br>\n  第二行</p>", "第一行\\\n第二行\n"},
+		{"分隔线", "<p>上</p><hr><p>下</p>", "上\n\n---\n\n下\n"},
+		{"链接", `<a href="https://example.com" title="示例">示例 </a>`, "[示例](https://example.com \"示例\")\n"},
+		{"空链接", `<a href="https://example.com"> </a><a>文本</a>`, "文本\n"},
+		{"图片", `<img src="/a.png" alt="图_1"><img alt="无地址">`, "![图\\_1](/a.png)\n"},
+		{"行内代码", "<p>运行 <code>go test</code> 或 <code>a`b</code> 或 <code>`x</code></p>", "运行 `go test` 或 ``a`b`` 或 `` `x ``\n"},
+		{
+			"代码块",
+			"<pre class=\"brush: php\">echo 1;\n\n\n  echo 2;</pre>",
+			"```php\necho 1;\n\n\n  echo 2;\n```\n",
+		},
+		{
+			"code 中的语言",
+			"<pre><code class=\"language-Go\">fmt.Println(\"```\")</code></pre>",
+			"````go\nfmt.Println(\"```\")\n````\n",
+		},
+		{"无序列表", "<ul><li>一</li><li>二</li></ul>", "- 一\n- 二\n"},
+		{"有序列表", "<ol><li>一</li><li>二</li></ol>", "1. 一\n2. 二\n"},
+		{
+			"嵌套列表",
+			"<ol><li>一<ul><li>子项</li></ul></li><li><p>二</p><p>续</p></li></ol>",
+			"1. 一\n   - 子项\n2. 二\n\n   续\n",
+		},
+		{"引用", "<blockquote><p>一</p><p>二</p></blockquote>", "> 一\n>\n> 二\n"},
+		{"脚本和样式", "<script>alert(1)</script><style>p{}</style><p>正文</p>", "正文\n"},
+		{
+			"表格保留为 HTML",
+			"<p>前</p><table><tr><td>a  b</td></tr></table>",
+			"前\n\n<table><tbody><tr><td>a  b</td></tr></tbody></table>\n",
+		},
+		{"未知元素只保留内容", "<p><span class=\"x\">文本</span></p>", "文本\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(tt.src)
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Convert() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestConvertRoundTrip 将转换结果重新渲染为 HTML，检查与原始 HTML 语义一致
+func TestConvertRoundTrip(t *testing.T) {
+	md := goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithRendererOptions(html.WithUnsafe()),
+	)
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"段落和强调", "<p>a <strong>b</strong> <em>c</em></p>", "<p>a <strong>b</strong> <em>c</em></p>"},
+		{"特殊字符", "<p>1*2_3 [x] `y`</p>", "<p>1*2_3 [x] `y`</p>"},
+		{"标题", "<h2>标题</h2>", "<h2>标题</h2>"},
+		{"链接和图片", `<p><a href="/a">链接</a> <img src="/b.png" alt="图"></p>`, `<p><a href="/a">链接</a> <img src="/b.png" alt="图"></p>`},
+		{"换行", "<p>一<br>二</p>", "<p>一<br>二</p>"},
+		{"删除线", "<p><del>删除</del></p>", "<p><del>删除</del></p>"},
+		{"行内代码", "<p><code>a`b</code></p>", "<p><code>a`b</code></p>"},
+		{"代码块", "<pre><code class=\"language-go\">a := 1\n\n\nb := 2\n</code></pre>", "<pre><code class=\"language-go\">a := 1\n\n\nb := 2\n</code></pre>"},
+		{"列表", "<ul><li>一</li><li>二<ol><li>子项</li></ol></li></ul>", "<ul><li>一</li><li>二<ol><li>子项</li></ol></li></ul>"},
+		{"引用", "<blockquote><p>一</p><p>二</p></blockquote>", "<blockquote><p>一</p><p>二</p></blockquote>"},
+		{"表格", "<table><tr><td>a</td></tr></table>", "<table><tbody><tr><td>a</td></tr></tbody></table>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			markdown, err := Convert(tt.src)
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			var b bytes.Buffer
+			if err := md.Convert([]byte(markdown), &b); err != nil {
+				t.Fatalf("goldmark Convert() error = %v", err)
+			}
+			if got := compact(b.String()); got != tt.want {
+				t.Errorf("round trip = %q, want %q\nmarkdown: %q", got, tt.want, markdown)
+			}
+		})
+	}
+}
+
+// compact 去掉块级元素之间的换行，便于比较渲染结果
+func compact(s string) string {
+	var b strings.Builder
+	inPre := false
+	for _, line := range strings.SplitAfter(strings.TrimSpace(s), "\n") {
+		if strings.Contains(line, "<pre") {
+			inPre = true
+		}
+		if strings.Contains(line, "</pre>") {
+			inPre = false
+		}
+		if !inPre {
+			line = strings.TrimSuffix(line, "\n")
+		}
+		b.WriteString(line)
+	}
+	return strings.ReplaceAll(b.String(), "<br />", "<br>")
+}
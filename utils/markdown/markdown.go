@@ -0,0 +1,225 @@
+// Package markdown 将文章的 Markdown 正文 (CommonMark + GFM 表格、删除线、任务列表、脚注)
+// 渲染为经过清理的 HTML，同时生成目录、标题锚点、代码高亮以及字数和阅读时长统计
+package markdown
+
+import (
+	"bytes"
+	"fmt"
+	"goblog/utils"
+	"goblog/utils/sanitize"
+	"math"
+	"strings"
+	"unicode"
+
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/yuin/goldmark"
+	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+)
+
+// 阅读速度: 中文每分钟字数、其他语言每分钟词数
+const (
+	cjkPerMinute  = 400
+	wordPerMinute = 200
+)
+
+// TocItem 是目录中的一个标题
+type TocItem struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	ID    string `json:"id"`
+}
+
+// Result 是渲染结果
+type Result struct {
+	HTML        string    `json:"html"`
+	Toc         []TocItem `json:"toc"`
+	WordCount   int       `json:"wordCount"`
+	ReadingTime int       `json:"readingTime"` // 分钟
+}
+
+var md = goldmark.New(
+	goldmark.WithExtensions(
+		extension.GFM,
+		extension.Footnote,
+		highlighting.NewHighlighting(
+			highlighting.WithStyle(utils.HighlightStyle),
+			highlighting.WithFormatOptions(chromahtml.WithClasses(true)),
+		),
+	),
+	goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+	// 原始 HTML 原样输出，渲染后统一按文章策略清理
+	goldmark.WithRendererOptions(html.WithUnsafe()),
+)
+
+// Render 渲染 Markdown 正文
+func Render(source string) (*Result, error) {
+	src := []byte(source)
+	ctx := parser.NewContext(parser.WithIDs(newHeadingIDs()))
+	doc := md.Parser().Parse(text.NewReader(src), parser.WithContext(ctx))
+
+	var buf bytes.Buffer
+	if err := md.Renderer().Render(&buf, src, doc); err != nil {
+		return nil, err
+	}
+
+	words := CountWords(source)
+	return &Result{
+		HTML:        sanitize.Article(buf.String()),
+		Toc:         toc(doc, src),
+		WordCount:   words.Total(),
+		ReadingTime: words.ReadingTime(),
+	}, nil
+}
+
+// SanitizeSource 清理 Markdown 源码中内嵌的原始 HTML (HTML 块和行内标签)，其余 Markdown 文本保持不变。
+// 只改写包含脚本等危险内容的片段 (见 sanitize.Unsafe)；普通标签即使不在策略允许范围内也保持原样，
+// 渲染时会对整个 HTML 统一清理。返回清理后的源码以及是否有内容被修改
+func SanitizeSource(source string, clean func(string) string) (string, bool) {
+	src := []byte(source)
+	doc := md.Parser().Parse(text.NewReader(src))
+
+	// 收集所有原始 HTML 片段的位置，按位置从后往前替换，避免前面的替换影响后面的偏移
+	var segments []text.Segment
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.HTMLBlock:
+			lines := node.Lines()
+			if lines.Len() > 0 {
+				stop := lines.At(lines.Len() - 1).Stop
+				if node.HasClosure() {
+					stop = node.ClosureLine.Stop
+				}
+				segments = append(segments, text.NewSegment(lines.At(0).Start, stop))
+			}
+		case *ast.RawHTML:
+			for i := 0; i < node.Segments.Len(); i++ {
+				segments = append(segments, node.Segments.At(i))
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+
+	changed := false
+	for i := len(segments) - 1; i >= 0; i-- {
+		seg := segments[i]
+		original := string(src[seg.Start:seg.Stop])
+		if !sanitize.Unsafe(original) {
+			continue
+		}
+		cleaned := clean(original)
+		if !sanitize.Changed(original, cleaned) {
+			continue
+		}
+		changed = true
+		src = append(src[:seg.Start:seg.Start], append([]byte(cleaned), src[seg.Stop:]...)...)
+	}
+	return string(src), changed
+}
+
+// toc 按文档顺序收集所有标题
+func toc(doc ast.Node, src []byte) []TocItem {
+	items := []TocItem{}
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		heading, ok := n.(*ast.Heading)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
+		id, _ := heading.AttributeString("id")
+		idBytes, _ := id.([]byte)
+		items = append(items, TocItem{
+			Level: heading.Level,
+			Text:  string(heading.Text(src)),
+			ID:    string(idBytes),
+		})
+		return ast.WalkSkipChildren, nil
+	})
+	return items
+}
+
+// headingIDs 用 utils.Slugify 生成标题锚点，中文标题转换为拼音，重复时追加数字后缀
+type headingIDs struct {
+	used map[string]bool
+}
+
+func newHeadingIDs() *headingIDs {
+	return &headingIDs{used: map[string]bool{}}
+}
+
+func (ids *headingIDs) Generate(value []byte, _ ast.NodeKind) []byte {
+	base := utils.Slugify(string(value))
+	if base == "" {
+		base = "heading"
+	}
+	id := base
+	for i := 1; ids.used[id]; i++ {
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+	ids.used[id] = true
+	return []byte(id)
+}
+
+func (ids *headingIDs) Put(value []byte) {
+	ids.used[string(value)] = true
+}
+
+// WordCount 是正文的字数统计，中文按字计，其他语言按词计
+type WordCount struct {
+	CJK   int
+	Words int
+}
+
+// Total 返回总字数
+func (w WordCount) Total() int {
+	return w.CJK + w.Words
+}
+
+// ReadingTime 估算阅读时长 (分钟)，有内容时至少为 1 分钟
+func (w WordCount) ReadingTime() int {
+	if w.Total() == 0 {
+		return 0
+	}
+	minutes := float64(w.CJK)/cjkPerMinute + float64(w.Words)/wordPerMinute
+	return max(int(math.Ceil(minutes)), 1)
+}
+
+// CountWords 统计文本的字数
+func CountWords(s string) WordCount {
+	var w WordCount
+	inWord := false
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r):
+			w.CJK++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				w.Words++
+			}
+			inWord = true
+		case r == '\'' || r == '-':
+			// 单词内的撇号和连字符不拆分单词
+		default:
+			inWord = false
+		}
+	}
+	return w
+}
+
+// HighlightCSS 返回代码高亮所用配色的样式表
+func HighlightCSS() (string, error) {
+	var b strings.Builder
+	formatter := chromahtml.New(chromahtml.WithClasses(true))
+	if err := formatter.WriteCSS(&b, styles.Get(utils.HighlightStyle)); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,230 @@
+// Package micropub 解析 W3C Micropub (https://www.w3.org/TR/micropub/) 请求。
+// 表单 (x-www-form-urlencoded、multipart/form-data) 和 JSON 两种格式统一转换为 Request，
+// 属性值为字符串或 JSON 对象 (如 {"html": "..."}、{"value": "...", "alt": "..."})
+package micropub
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// 请求的操作
+const (
+	ActionCreate   = "create"
+	ActionUpdate   = "update"
+	ActionDelete   = "delete"
+	ActionUndelete = "undelete"
+)
+
+// 错误码
+const (
+	ErrInvalidRequest = "invalid_request"
+	ErrUnauthorized   = "unauthorized"
+	ErrForbidden      = "forbidden"
+	ErrServerError    = "server_error"
+)
+
+// Error 是 Micropub 的错误响应
+type Error struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func (e *Error) Error() string {
+	if e.Description == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Description
+}
+
+// invalid 返回 invalid_request 错误
+func invalid(format string, args ...any) *Error {
+	return &Error{Code: ErrInvalidRequest, Description: fmt.Sprintf(format, args...)}
+}
+
+// Properties 是 h-entry 的属性，每个属性可以有多个值
+type Properties map[string][]any
+
+// Request 是一个 Micropub 请求
+type Request struct {
+	Action     string
+	URL        string     // 更新、删除、恢复的文章地址
+	Type       string     // 创建的类型，如 entry
+	Properties Properties // 创建时的属性
+
+	// 更新操作，只能通过 JSON 提交
+	Replace   Properties // 替换属性的所有值
+	Add       Properties // 为属性追加值
+	Delete    Properties // 删除属性中的部分值
+	DeleteAll []string   // 删除整个属性
+}
+
+// ParseRequest 解析请求体，multipart 请求的文件保留在 r.MultipartForm 中，由调用方处理
+func ParseRequest(r *http.Request, maxMemory int64) (*Request, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/json":
+		return parseJSON(r)
+	case "multipart/form-data":
+		if err := r.ParseMultipartForm(maxMemory); err != nil {
+			return nil, invalid("无法解析表单: %v", err)
+		}
+		return parseForm(r.PostForm)
+	case "application/x-www-form-urlencoded":
+		if err := r.ParseForm(); err != nil {
+			return nil, invalid("无法解析表单: %v", err)
+		}
+		return parseForm(r.PostForm)
+	default:
+		return nil, invalid("不支持的 Content-Type: %s", mediaType)
+	}
+}
+
+// parseForm 解析表单请求。表单只能创建、删除和恢复文章，多值属性的名称以 [] 结尾
+func parseForm(values url.Values) (*Request, error) {
+	req := &Request{
+		Action:     values.Get("action"),
+		URL:        values.Get("url"),
+		Type:       values.Get("h"),
+		Properties: Properties{},
+	}
+	if req.Action == "" {
+		req.Action = ActionCreate
+	}
+	switch req.Action {
+	case ActionCreate:
+	case ActionDelete, ActionUndelete:
+		return req, req.check()
+	default:
+		return nil, invalid("表单请求不支持操作 %s，更新文章请使用 JSON", req.Action)
+	}
+
+	for key, vals := range values {
+		switch key {
+		case "h", "action", "access_token":
+			continue
+		}
+		name := strings.TrimSuffix(key, "[]")
+		for _, v := range vals {
+			req.Properties[name] = append(req.Properties[name], v)
+		}
+	}
+	return req, req.check()
+}
+
+// jsonRequest 是 JSON 请求体
+type jsonRequest struct {
+	Type       []string        `json:"type"`
+	Properties Properties      `json:"properties"`
+	Action     string          `json:"action"`
+	URL        string          `json:"url"`
+	Replace    Properties      `json:"replace"`
+	Add        Properties      `json:"add"`
+	Delete     json.RawMessage `json:"delete"` // 属性名数组或属性值对象
+}
+
+// parseJSON 解析 JSON 请求
+func parseJSON(r *http.Request) (*Request, error) {
+	var body jsonRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, invalid("请求体过大")
+		}
+		return nil, invalid("无法解析 JSON: %v", err)
+	}
+
+	req := &Request{
+		Action:     body.Action,
+		URL:        body.URL,
+		Properties: body.Properties,
+		Replace:    body.Replace,
+		Add:        body.Add,
+	}
+	if req.Action == "" {
+		req.Action = ActionCreate
+	}
+	if len(body.Type) > 0 {
+		req.Type = strings.TrimPrefix(body.Type[0], "h-")
+	}
+	if len(body.Delete) > 0 && req.Action == ActionUpdate {
+		if err := json.Unmarshal(body.Delete, &req.DeleteAll); err != nil {
+			if err := json.Unmarshal(body.Delete, &req.Delete); err != nil {
+				return nil, invalid("delete 必须是属性名数组或属性值对象")
+			}
+		}
+	}
+	return req, req.check()
+}
+
+// check 检查请求的必填参数
+func (req *Request) check() error {
+	switch req.Action {
+	case ActionCreate:
+		if req.Type != "entry" {
+			return invalid("只支持创建 h-entry")
+		}
+		if req.Properties == nil {
+			req.Properties = Properties{}
+		}
+	case ActionUpdate, ActionDelete, ActionUndelete:
+		if req.URL == "" {
+			return invalid("缺少文章地址 url")
+		}
+	default:
+		return invalid("不支持的操作 %s", req.Action)
+	}
+	return nil
+}
+
+// Has 返回属性是否存在
+func (p Properties) Has(name string) bool {
+	_, ok := p[name]
+	return ok
+}
+
+// String 返回属性的第一个值的文本
+func (p Properties) String(name string) string {
+	if vals := p[name]; len(vals) > 0 {
+		return Text(vals[0])
+	}
+	return ""
+}
+
+// Strings 返回属性所有值的文本
+func (p Properties) Strings(name string) []string {
+	vals := make([]string, 0, len(p[name]))
+	for _, v := range p[name] {
+		vals = append(vals, Text(v))
+	}
+	return vals
+}
+
+// Text 返回属性值的文本: 字符串本身，或对象的 html、value 成员 (如 content、photo)
+func Text(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case map[string]any:
+		for _, key := range []string{"html", "value"} {
+			if s, ok := v[key].(string); ok {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// Member 返回对象属性值的成员，如 photo 的 alt
+func Member(v any, key string) string {
+	if m, ok := v.(map[string]any); ok {
+		s, _ := m[key].(string)
+		return s
+	}
+	return ""
+}
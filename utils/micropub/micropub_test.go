@@ -0,0 +1,252 @@
+package micropub
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseRequestForm(t *testing.T) {
+	tests := []struct {
+		name string
+		form url.Values
+		want *Request
+	}{
+		{
+			name: "创建",
+			form: url.Values{
+				"h":            {"entry"},
+				"content":      {"正文"},
+				"category[]":   {"Go", "笔记"},
+				"access_token": {"secret"},
+			},
+			want: &Request{
+				Action:     ActionCreate,
+				Type:       "entry",
+				Properties: Properties{"content": {"正文"}, "category": {"Go", "笔记"}},
+			},
+		},
+		{
+			name: "删除",
+			form: url.Values{"action": {"delete"}, "url": {"https://example.com/articles/1"}, "content": {"忽略"}},
+			want: &Request{
+				Action:     ActionDelete,
+				URL:        "https://example.com/articles/1",
+				Properties: Properties{},
+			},
+		},
+		{
+			name: "恢复",
+			form: url.Values{"action": {"undelete"}, "url": {"https://example.com/articles/1"}},
+			want: &Request{
+				Action:     ActionUndelete,
+				URL:        "https://example.com/articles/1",
+				Properties: Properties{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/micropub", strings.NewReader(tt.form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+			got, err := ParseRequest(r, 1<<20)
+			if err != nil {
+				t.Fatalf("ParseRequest() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRequest() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRequestMultipart(t *testing.T) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	w.WriteField("h", "entry")
+	w.WriteField("content", "图片")
+	w.WriteField("category", "相册")
+	fw, err := w.CreateFormFile("photo", "a.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write([]byte("jpeg"))
+	w.Close()
+
+	r := httptest.NewRequest(http.MethodPost, "/micropub", &body)
+	r.Header.Set("Content-Type", w.FormDataContentType())
+	got, err := ParseRequest(r, 1<<20)
+	if err != nil {
+		t.Fatalf("ParseRequest() error = %v", err)
+	}
+	want := &Request{
+		Action:     ActionCreate,
+		Type:       "entry",
+		Properties: Properties{"content": {"图片"}, "category": {"相册"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseRequest() = %+v, want %+v", got, want)
+	}
+	// 文件留给调用方处理，不出现在属性中
+	if files := r.MultipartForm.File["photo"]; len(files) != 1 || files[0].Filename != "a.jpg" {
+		t.Errorf("MultipartForm.File[photo] = %v", files)
+	}
+}
+
+func TestParseRequestJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want *Request
+	}{
+		{
+			name: "创建",
+			body: `{"type": ["h-entry"], "properties": {"name": ["标题"], "content": [{"html": "<p>正文</p>"}], "photo": [{"value": "/a.jpg", "alt": "图"}]}}`,
+			want: &Request{
+				Action: ActionCreate,
+				Type:   "entry",
+				Properties: Properties{
+					"name":    {"标题"},
+					"content": {map[string]any{"html": "<p>正文</p>"}},
+					"photo":   {map[string]any{"value": "/a.jpg", "alt": "图"}},
+				},
+			},
+		},
+		{
+			name: "没有属性",
+			body: `{"type": ["h-entry"]}`,
+			want: &Request{Action: ActionCreate, Type: "entry", Properties: Properties{}},
+		},
+		{
+			name: "更新",
+			body: `{"action": "update", "url": "https://example.com/articles/1",
+				"replace": {"content": ["新正文"]}, "add": {"category": ["新分类"]}, "delete": ["summary"]}`,
+			want: &Request{
+				Action:    ActionUpdate,
+				URL:       "https://example.com/articles/1",
+				Replace:   Properties{"content": {"新正文"}},
+				Add:       Properties{"category": {"新分类"}},
+				DeleteAll: []string{"summary"},
+			},
+		},
+		{
+			name: "删除部分属性值",
+			body: `{"action": "update", "url": "https://example.com/articles/1", "delete": {"category": ["旧分类"]}}`,
+			want: &Request{
+				Action: ActionUpdate,
+				URL:    "https://example.com/articles/1",
+				Delete: Properties{"category": {"旧分类"}},
+			},
+		},
+		{
+			name: "删除",
+			body: `{"action": "delete", "url": "https://example.com/articles/1"}`,
+			want: &Request{Action: ActionDelete, URL: "https://example.com/articles/1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/micropub", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", "application/json")
+			got, err := ParseRequest(r, 1<<20)
+			if err != nil {
+				t.Fatalf("ParseRequest() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRequest() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRequestInvalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{"不支持的 Content-Type", "text/plain", "h=entry"},
+		{"缺少 Content-Type", "", "h=entry"},
+		{"表单缺少类型", "application/x-www-form-urlencoded", "content=x"},
+		{"表单创建其他类型", "application/x-www-form-urlencoded", "h=event&name=x"},
+		{"表单更新", "application/x-www-form-urlencoded", "action=update&url=https://example.com/articles/1"},
+		{"表单删除缺少地址", "application/x-www-form-urlencoded", "action=delete"},
+		{"错误的 multipart 边界", "multipart/form-data; boundary=x", "h=entry"},
+		{"无效的 JSON", "application/json", `{"type": `},
+		{"JSON 创建其他类型", "application/json", `{"type": ["h-event"], "properties": {}}`},
+		{"JSON 更新缺少地址", "application/json", `{"action": "update", "replace": {"name": ["x"]}}`},
+		{"JSON 不支持的操作", "application/json", `{"action": "archive", "url": "https://example.com/articles/1"}`},
+		{"JSON 错误的 delete", "application/json", `{"action": "update", "url": "https://example.com/articles/1", "delete": "name"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/micropub", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				r.Header.Set("Content-Type", tt.contentType)
+			}
+			_, err := ParseRequest(r, 1<<20)
+			var mpErr *Error
+			if !errors.As(err, &mpErr) || mpErr.Code != ErrInvalidRequest {
+				t.Errorf("ParseRequest() error = %v, want %s", err, ErrInvalidRequest)
+			}
+		})
+	}
+}
+
+func TestParseRequestTooLarge(t *testing.T) {
+	body := `{"type": ["h-entry"], "properties": {"content": ["` + strings.Repeat("a", 1024) + `"]}}`
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, "/micropub", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	r.Body = http.MaxBytesReader(w, r.Body, 64)
+	_, err := ParseRequest(r, 1<<20)
+	var mpErr *Error
+	if !errors.As(err, &mpErr) || mpErr.Description != "请求体过大" {
+		t.Errorf("ParseRequest() error = %v, want 请求体过大", err)
+	}
+}
+
+func TestProperties(t *testing.T) {
+	props := Properties{
+		"name":     {"标题"},
+		"content":  {map[string]any{"html": "<p>正文</p>", "value": "正文"}},
+		"photo":    {map[string]any{"value": "/a.jpg", "alt": "图"}, "/b.jpg"},
+		"category": {"Go", 1},
+		"empty":    {},
+	}
+
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{"Has", props.Has("empty"), true},
+		{"Has 不存在的属性", props.Has("summary"), false},
+		{"String", props.String("name"), "标题"},
+		{"String 优先使用 html", props.String("content"), "<p>正文</p>"},
+		{"String 空属性", props.String("empty"), ""},
+		{"Strings", props.Strings("photo"), []string{"/a.jpg", "/b.jpg"}},
+		{"Strings 非字符串的值为空", props.Strings("category"), []string{"Go", ""}},
+		{"Strings 不存在的属性", props.Strings("summary"), []string{}},
+		{"Member", Member(props["photo"][0], "alt"), "图"},
+		{"Member 字符串值", Member(props["photo"][1], "alt"), ""},
+		{"Text 不支持的类型", Text(map[string]any{"url": "/a"}), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.got, tt.want) {
+				t.Errorf("got %#v, want %#v", tt.got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+// Package sanitize 按配置的策略清理用户提交的 HTML，防止 XSS。
+// 文章和评论使用不同的策略，默认文章较宽松 (rich)，评论较严格 (basic)
+package sanitize
+
+import (
+	"goblog/utils"
+	"html"
+	"regexp"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	nethtml "golang.org/x/net/html"
+)
+
+// 可选的清理策略，从严到宽
+const (
+	PolicyText  = "text"  // 移除所有标签，只保留文本
+	PolicyBasic = "basic" // 段落、换行、强调、行内代码和链接
+	PolicyUGC   = "ugc"   // 常见的用户内容: 标题、列表、表格、图片、引用、代码块等
+	PolicyRich  = "rich"  // 在 ugc 基础上允许代码高亮、脚注、标题锚点、任务列表和白名单站点的 iframe
+)
+
+var (
+	articlePolicy = newPolicy(utils.SanitizeArticle)
+	commentPolicy = newPolicy(utils.SanitizeComment)
+)
+
+// Article 按文章策略清理 HTML
+func Article(s string) string {
+	return articlePolicy.Sanitize(s)
+}
+
+// Comment 按评论策略清理 HTML
+func Comment(s string) string {
+	return commentPolicy.Sanitize(s)
+}
+
+// Changed 判断清理是否实际改变了内容，忽略仅由实体转义造成的差异 (例如 & 变为 &amp;)
+func Changed(original, sanitized string) bool {
+	return html.UnescapeString(original) != html.UnescapeString(sanitized)
+}
+
+// unsafeElements 是可以执行脚本、嵌入外部内容或改变整个页面的元素
+var unsafeElements = map[string]bool{
+	"script": true, "style": true, "iframe": true, "frame": true, "frameset": true,
+	"object": true, "embed": true, "applet": true, "base": true, "link": true,
+	"meta": true, "form": true, "svg": true, "math": true, "template": true,
+}
+
+// urlAttrs 是值为地址的属性
+var urlAttrs = map[string]bool{
+	"href": true, "src": true, "action": true, "formaction": true, "xlink:href": true,
+	"poster": true, "background": true, "lowsrc": true, "dynsrc": true, "data": true,
+}
+
+// safeDataURI 是允许的 data URI (图片)
+var safeDataURI = regexp.MustCompile(`^data:image/(png|jpe?g|gif|webp);`)
+
+// Unsafe 判断 HTML 片段是否包含可能执行脚本的内容: 危险元素、事件属性 (on*)、javascript: 等地址。
+// 普通标签和属性即使不在清理策略的允许范围内也不算在内，它们在渲染时统一清理，不需要改写用户的源码
+func Unsafe(fragment string) bool {
+	z := nethtml.NewTokenizer(strings.NewReader(fragment))
+	for {
+		switch z.Next() {
+		case nethtml.ErrorToken:
+			return false
+		case nethtml.StartTagToken, nethtml.SelfClosingTagToken, nethtml.EndTagToken:
+			name, hasAttr := z.TagName()
+			if unsafeElements[string(name)] {
+				return true
+			}
+			for hasAttr {
+				var key, val []byte
+				key, val, hasAttr = z.TagAttr()
+				attr := string(key)
+				switch {
+				case strings.HasPrefix(attr, "on"):
+					return true
+				case attr == "style" && unsafeStyle(string(val)):
+					return true
+				case urlAttrs[attr] && unsafeURL(string(val)):
+					return true
+				}
+			}
+		}
+	}
+}
+
+// normalize 去掉空白和控制字符并转为小写，浏览器解析地址时会忽略这些字符 (如 java\tscript:)
+func normalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, strings.ToLower(s))
+}
+
+// unsafeURL 判断地址是否使用可以执行脚本的协议
+func unsafeURL(v string) bool {
+	v = normalize(v)
+	switch {
+	case strings.HasPrefix(v, "javascript:"), strings.HasPrefix(v, "vbscript:"):
+		return true
+	case strings.HasPrefix(v, "data:"):
+		return !safeDataURI.MatchString(v)
+	}
+	return false
+}
+
+// unsafeStyle 判断样式是否包含脚本 (旧版 IE 的 expression 和 javascript: 地址)
+func unsafeStyle(v string) bool {
+	v = normalize(v)
+	return strings.Contains(v, "expression(") || strings.Contains(v, "javascript:")
+}
+
+func newPolicy(name string) *bluemonday.Policy {
+	switch name {
+	case PolicyText:
+		return bluemonday.StrictPolicy()
+	case PolicyBasic:
+		p := bluemonday.NewPolicy()
+		p.AllowElements("p", "br", "b", "strong", "i", "em", "del", "s", "code")
+		p.AllowStandardURLs()
+		p.AllowAttrs("href").OnElements("a")
+		p.RequireNoFollowOnLinks(true)
+		p.AddTargetBlankToFullyQualifiedLinks(true)
+		return p
+	case PolicyUGC:
+		return bluemonday.UGCPolicy()
+	default:
+		p := bluemonday.UGCPolicy()
+		p.AllowAttrs("class").Matching(regexp.MustCompile(`^[a-zA-Z0-9_\- ]+$`)).
+			OnElements("pre", "code", "span", "div", "a", "sup", "li", "ul", "ol", "input")
+		p.AllowAttrs("id").Matching(regexp.MustCompile(`^[a-zA-Z0-9_:\-]+$`)).Globally()
+		p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+		p.AllowAttrs("checked", "disabled").OnElements("input")
+		if len(utils.SanitizeIframeHosts) > 0 {
+			hosts := make([]string, 0, len(utils.SanitizeIframeHosts))
+			for _, h := range utils.SanitizeIframeHosts {
+				hosts = append(hosts, regexp.QuoteMeta(strings.TrimSpace(h)))
+			}
+			src := regexp.MustCompile(`^https://(` + strings.Join(hosts, "|") + `)/`)
+			p.AllowAttrs("src").Matching(src).OnElements("iframe")
+			p.AllowAttrs("width", "height").Matching(bluemonday.Integer).OnElements("iframe")
+			p.AllowAttrs("allowfullscreen", "frameborder").OnElements("iframe")
+		}
+		return p
+	}
+}
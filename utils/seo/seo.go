@@ -0,0 +1,133 @@
+// Package seo 将页面的标题、OpenGraph、Twitter Card 和 JSON-LD 结构化数据注入前台 index.html，
+// 使不执行 JavaScript 的爬虫和链接预览也能获取页面信息
+package seo
+
+import (
+	"bytes"
+	"html/template"
+	"regexp"
+	"time"
+)
+
+// Page 是一个前台页面的元信息
+type Page struct {
+	SiteName    string
+	Title       string
+	Description string
+	URL         string // 页面的规范地址
+	Image       string
+	Type        string // OpenGraph 类型: website 或 article
+	FeedURL     string
+	MicropubURL string // Micropub 接口地址，供 IndieWeb 客户端发现，仅首页设置
+
+	// 以下字段仅用于文章页
+	Author    string
+	Section   string
+	Tags      []string
+	Published time.Time
+	Modified  time.Time
+
+	JSONLD any // schema.org 结构化数据，会被编码为 JSON
+
+	// 供不执行 JavaScript 的客户端阅读的正文，输出在 <noscript> 中
+	Heading    string
+	Paragraphs []string
+	Content    template.HTML // 已清理的正文 HTML
+	Links      []Link
+}
+
+// Link 是正文中的一个链接，例如分类页的文章列表
+type Link struct {
+	Title string
+	URL   string
+}
+
+var headTmpl = template.Must(template.New("head").Parse(`
+<title>{{.Title}}</title>
+<meta name="description" content="{{.Description}}" />
+<link rel="canonical" href="{{.URL}}" />
+{{- if .FeedURL}}
+<link rel="alternate" type="application/rss+xml" title="{{.SiteName}}" href="{{.FeedURL}}" />
+{{- end}}
+{{- if .MicropubURL}}
+<link rel="micropub" href="{{.MicropubURL}}" />
+{{- end}}
+<meta property="og:site_name" content="{{.SiteName}}" />
+<meta property="og:type" content="{{.Type}}" />
+<meta property="og:title" content="{{.Title}}" />
+<meta property="og:description" content="{{.Description}}" />
+<meta property="og:url" content="{{.URL}}" />
+{{- if .Image}}
+<meta property="og:image" content="{{.Image}}" />
+{{- end}}
+{{- if eq .Type "article"}}
+<meta property="article:published_time" content="{{.Published.Format "2006-01-02T15:04:05Z07:00"}}" />
+<meta property="article:modified_time" content="{{.Modified.Format "2006-01-02T15:04:05Z07:00"}}" />
+{{- if .Author}}
+<meta property="article:author" content="{{.Author}}" />
+{{- end}}
+{{- if .Section}}
+<meta property="article:section" content="{{.Section}}" />
+{{- end}}
+{{- range .Tags}}
+<meta property="article:tag" content="{{.}}" />
+{{- end}}
+{{- end}}
+<meta name="twitter:card" content="{{if .Image}}summary_large_image{{else}}summary{{end}}" />
+<meta name="twitter:title" content="{{.Title}}" />
+<meta name="twitter:description" content="{{.Description}}" />
+{{- if .Image}}
+<meta name="twitter:image" content="{{.Image}}" />
+{{- end}}
+{{- if .JSONLD}}
+<script type="application/ld+json">{{.JSONLD}}</script>
+{{- end}}
+`))
+
+var bodyTmpl = template.Must(template.New("body").Parse(`
+<noscript>
+<article>
+<h1>{{.Heading}}</h1>
+{{- range .Paragraphs}}
+<p>{{.}}</p>
+{{- end}}
+{{- if .Content}}
+{{.Content}}
+{{- end}}
+{{- if .Links}}
+<ul>
+{{- range .Links}}
+<li><a href="{{.URL}}">{{.Title}}</a></li>
+{{- end}}
+</ul>
+{{- end}}
+</article>
+</noscript>
+`))
+
+var (
+	titleRe       = regexp.MustCompile(`(?is)<title>.*?</title>\s*`)
+	descriptionRe = regexp.MustCompile(`(?is)<meta\s+name="description"[^>]*>\s*`)
+)
+
+// Inject 替换 index.html 中的标题和描述，在 </head> 前插入元信息，在 <body> 后插入 <noscript> 正文
+func Inject(index []byte, p *Page) ([]byte, error) {
+	var head, body bytes.Buffer
+	if err := headTmpl.Execute(&head, p); err != nil {
+		return nil, err
+	}
+	if err := bodyTmpl.Execute(&body, p); err != nil {
+		return nil, err
+	}
+
+	out := titleRe.ReplaceAll(index, nil)
+	out = descriptionRe.ReplaceAll(out, nil)
+	out = bytes.Replace(out, []byte("</head>"), append(head.Bytes(), "</head>"...), 1)
+	if i := bytes.Index(out, []byte("<body")); i >= 0 {
+		if j := bytes.IndexByte(out[i:], '>'); j >= 0 {
+			pos := i + j + 1
+			out = append(out[:pos:pos], append(body.Bytes(), out[pos:]...)...)
+		}
+	}
+	return out, nil
+}
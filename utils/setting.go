@@ -2,17 +2,24 @@
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"gopkg.in/ini.v1"
 )
 
+// DefaultJwtKey 是未配置 JwtKey 时使用的默认密钥，release 模式下禁止使用
+const DefaultJwtKey = "45df45rds4"
+
 // 解析配置文件并设置参数
 var (
-	AppMode   string
-	HttpPort  string
-	FrontPort string
-	AdminPort string
-	JwtKey    string
+	AppMode    string
+	HttpPort   string
+	FrontPort  string
+	AdminPort  string
+	JwtKey     string
+	JwtAlg     string
+	JwtKeyFile string
 
 	Db         string
 	DbHost     string
@@ -34,6 +41,40 @@
 	ServerPort   string
 	FromEmail    string
 	FromPassword string
+
+	RegisterMode   string
+	AllowedDomains []string
+
+	SearchEngine string
+
+	ViewDedupWindow   time.Duration
+	ViewFlushInterval time.Duration
+
+	ReactionTypes []string
+
+	SiteTitle string
+	SiteDesc  string
+	SiteURL   string
+	SiteAPI   string
+
+	FeedContent string
+	FeedLimit   int
+
+	RobotsFile     string
+	RobotsDisallow []string
+
+	HighlightStyle string
+
+	SanitizeArticle     string
+	SanitizeComment     string
+	SanitizeIframeHosts []string
+
+	TrashRetention time.Duration
+
+	AutosaveFlushInterval time.Duration
+	AutosaveTTL           time.Duration
+
+	ClientDefaultCategory string
 )
 
 func init() {
@@ -48,6 +89,18 @@ func init() {
 	LoadQiniu(file)
 	LoadRedis(file)
 	LoadEmailServer(file)
+	LoadRegister(file)
+	LoadSearch(file)
+	LoadView(file)
+	LoadReaction(file)
+	LoadSite(file)
+	LoadFeed(file)
+	LoadRobots(file)
+	LoadMarkdown(file)
+	LoadSanitize(file)
+	LoadTrash(file)
+	LoadAutosave(file)
+	LoadClient(file)
 }
 
 func LoadServer(file *ini.File) {
@@ -55,7 +108,9 @@ func LoadServer(file *ini.File) {
 	HttpPort = file.Section("server").Key("HttpPort").MustString(":8080")
 	FrontPort = file.Section("server").Key("FrontPort").MustString(":3000")
 	AdminPort = file.Section("server").Key("AdminPort").MustString(":5000")
-	JwtKey = file.Section("server").Key("JwtKey").MustString("45df45rds4")
+	JwtKey = file.Section("server").Key("JwtKey").MustString(DefaultJwtKey)
+	JwtAlg = file.Section("server").Key("JwtAlg").MustString("HS256")
+	JwtKeyFile = file.Section("server").Key("JwtKeyFile").MustString("config/jwt_keys.json")
 }
 
 func LoadDate(file *ini.File) {
@@ -88,3 +143,105 @@ func LoadEmailServer(file *ini.File) {
 	FromEmail = emailSection.Key("FromEmail").String()
 	FromPassword = emailSection.Key("FromPassword").String()
 }
+
+// 注册模式: open 开放注册, invite 仅邀请码注册, closed 关闭注册, domain 仅允许指定邮箱域名注册
+const (
+	RegisterModeOpen   = "open"
+	RegisterModeInvite = "invite"
+	RegisterModeClosed = "closed"
+	RegisterModeDomain = "domain"
+)
+
+func LoadRegister(file *ini.File) {
+	var registerSection = file.Section("register")
+	RegisterMode = registerSection.Key("Mode").In(RegisterModeOpen,
+		[]string{RegisterModeOpen, RegisterModeInvite, RegisterModeClosed, RegisterModeDomain})
+	AllowedDomains = registerSection.Key("AllowedDomains").Strings(",")
+}
+
+// LoadSearch 读取全文搜索配置，Engine 可选 auto、mysql、memory，
+// auto 在使用 MySQL 时采用 FULLTEXT 索引，否则使用内置的内存索引
+func LoadSearch(file *ini.File) {
+	SearchEngine = file.Section("search").Key("Engine").In("auto", []string{"auto", "mysql", "memory"})
+}
+
+// LoadView 读取阅读量统计配置：同一访客在 DedupMinutes 内重复访问只计一次，
+// Redis 中累计的阅读量每 FlushSeconds 秒写回数据库
+func LoadView(file *ini.File) {
+	var viewSection = file.Section("view")
+	ViewDedupWindow = time.Duration(viewSection.Key("DedupMinutes").MustInt(30)) * time.Minute
+	ViewFlushInterval = time.Duration(viewSection.Key("FlushSeconds").MustInt(60)) * time.Second
+}
+
+// LoadReaction 读取可用的表情回应类型，like (点赞) 始终可用
+func LoadReaction(file *ini.File) {
+	ReactionTypes = file.Section("reaction").Key("Types").Strings(",")
+	if len(ReactionTypes) == 0 {
+		ReactionTypes = []string{"heart", "laugh", "hooray", "confused", "rocket", "eyes"}
+	}
+}
+
+// LoadSite 读取站点信息，URL 为前台页面的公开地址，API 为接口的公开地址 (包含 /api/v1)，
+// 用于生成订阅源、站点地图等需要绝对链接的内容
+func LoadSite(file *ini.File) {
+	var siteSection = file.Section("site")
+	SiteTitle = siteSection.Key("Title").MustString("GoBlog")
+	SiteDesc = siteSection.Key("Description").String()
+	SiteURL = strings.TrimRight(siteSection.Key("URL").MustString("http://localhost"+FrontPort), "/")
+	SiteAPI = strings.TrimRight(siteSection.Key("API").MustString("http://localhost"+HttpPort+"/api/v1"), "/")
+}
+
+// 订阅源正文输出方式: full 输出全文, summary 只输出摘要
+const (
+	FeedContentFull    = "full"
+	FeedContentSummary = "summary"
+)
+
+// LoadFeed 读取订阅源配置，Limit 为每个订阅源包含的最新文章数
+func LoadFeed(file *ini.File) {
+	var feedSection = file.Section("feed")
+	FeedContent = feedSection.Key("Content").In(FeedContentFull, []string{FeedContentFull, FeedContentSummary})
+	FeedLimit = feedSection.Key("Limit").MustInt(20)
+}
+
+// LoadRobots 读取 robots.txt 配置：指定 File 时直接返回该文件，
+// 否则根据 Disallow (逗号分隔的路径) 生成，并附上站点地图地址
+func LoadRobots(file *ini.File) {
+	var robotsSection = file.Section("robots")
+	RobotsFile = robotsSection.Key("File").String()
+	RobotsDisallow = robotsSection.Key("Disallow").Strings(",")
+}
+
+// LoadMarkdown 读取 Markdown 渲染配置，HighlightStyle 为代码高亮配色 (chroma 样式名)
+func LoadMarkdown(file *ini.File) {
+	HighlightStyle = file.Section("markdown").Key("HighlightStyle").MustString("github")
+}
+
+// LoadSanitize 读取 HTML 清理策略，Article 和 Comment 可选 text、basic、ugc、rich (由严到宽)，
+// IframeHosts 为 rich 策略下允许嵌入 iframe 的站点 (逗号分隔，例如 player.bilibili.com)
+func LoadSanitize(file *ini.File) {
+	var sanitizeSection = file.Section("sanitize")
+	policies := []string{"text", "basic", "ugc", "rich"}
+	SanitizeArticle = sanitizeSection.Key("Article").In("rich", policies)
+	SanitizeComment = sanitizeSection.Key("Comment").In("basic", policies)
+	SanitizeIframeHosts = sanitizeSection.Key("IframeHosts").Strings(",")
+}
+
+// LoadTrash 读取回收站配置，移入回收站超过 RetentionDays 天的内容会被彻底删除，为 0 表示永久保留
+func LoadTrash(file *ini.File) {
+	TrashRetention = time.Duration(file.Section("trash").Key("RetentionDays").MustInt(30)) * 24 * time.Hour
+}
+
+// LoadAutosave 读取编辑器自动保存配置：Redis 中的自动保存每 FlushSeconds 秒写入数据库，
+// 在 Redis 中保留 TTLHours 小时 (过期后从数据库读取)
+func LoadAutosave(file *ini.File) {
+	autosaveSection := file.Section("autosave")
+	AutosaveFlushInterval = time.Duration(autosaveSection.Key("FlushSeconds").MustInt(30)) * time.Second
+	AutosaveTTL = time.Duration(autosaveSection.Key("TTLHours").MustInt(168)) * time.Hour
+}
+
+// LoadClient 读取写作客户端 (MetaWeblog、Micropub) 配置，客户端提交的文章没有分类时归入 DefaultCategory，
+// 分类不存在时自动创建
+func LoadClient(file *ini.File) {
+	ClientDefaultCategory = file.Section("client").Key("DefaultCategory").MustString("未分类")
+}
@@ -0,0 +1,197 @@
+// Package sitegen 将博客渲染为纯静态 HTML 页面，生成的目录不依赖 Go 服务和前台脚本即可托管。
+// 页面之间使用相对路径链接，因此站点可以部署在任意子路径下，也可以直接在本地打开
+package sitegen
+
+import (
+	"html/template"
+	"io"
+	"time"
+)
+
+// Site 是站点的公共信息
+type Site struct {
+	Title       string
+	Description string
+	URL         string // 站点的公开地址，用于规范链接
+}
+
+// Link 是一个站内链接，URL 相对于站点根目录 (如 articles/1/index.html)
+type Link struct {
+	Title string
+	URL   string
+	Count int64 // 分类、标签下的文章数，0 时不显示
+}
+
+// Summary 是文章列表中的一篇文章
+type Summary struct {
+	Title    string
+	URL      string
+	Summary  string
+	Date     time.Time
+	Pinned   bool
+	Category Link
+	Tags     []Link
+}
+
+// Comment 是文章下的一条评论
+type Comment struct {
+	Commentator string
+	Content     template.HTML // 已按评论策略清理的内容
+	Date        time.Time
+	Reply       bool // 回复其他评论
+}
+
+// Article 是文章页的正文
+type Article struct {
+	Content  template.HTML // 已清理的正文 HTML
+	Image    string
+	Author   string
+	Date     time.Time
+	Updated  time.Time
+	Category Link
+	Tags     []Link
+	Prev     *Link // 上一篇 (更早发布)
+	Next     *Link // 下一篇
+	Comments []Comment
+}
+
+// Page 是一个静态页面。Article 不为空时为文章页，否则为文章列表 (Articles) 或索引页 (Links)
+type Page struct {
+	Site        Site
+	Root        string // 从当前页面到站点根目录的相对路径，如 ../../
+	Title       string
+	Description string
+	Canonical   string
+	Heading     string
+	Articles    []Summary
+	Links       []Link
+	Prev        string // 列表的上一页、下一页，相对于站点根目录
+	Next        string
+	Article     *Article
+}
+
+// Stylesheet 是所有页面共用的样式表，写入站点根目录的 StylesheetName
+const Stylesheet = `*{box-sizing:border-box}
+body{margin:0;font:16px/1.7 -apple-system,BlinkMacSystemFont,"Segoe UI","PingFang SC","Microsoft YaHei",sans-serif;color:#222;background:#fafafa}
+a{color:#2563eb;text-decoration:none}a:hover{text-decoration:underline}
+header,main,footer{max-width:760px;margin:0 auto;padding:0 20px}
+header{padding-top:32px;padding-bottom:16px;border-bottom:1px solid #e5e5e5}
+header .site{font-size:24px;font-weight:700;color:#222}
+header p{margin:4px 0 0;color:#666}
+nav a{margin-right:16px}
+main{padding-top:24px;padding-bottom:48px}
+footer{padding-top:16px;padding-bottom:32px;color:#888;font-size:14px;border-top:1px solid #e5e5e5}
+.meta{color:#888;font-size:14px}.meta a{color:#888}
+.tag{margin-right:8px}
+.entry{padding:16px 0;border-bottom:1px solid #eee}.entry h2{margin:0 0 4px;font-size:20px}
+.pinned{color:#dc2626;font-size:13px;margin-right:6px}
+.pager{display:flex;justify-content:space-between;margin-top:24px}
+.content img,.content video,.content iframe{max-width:100%}
+.content pre{overflow:auto;padding:12px;background:#f3f4f6;border-radius:4px}
+.content table{border-collapse:collapse}.content td,.content th{border:1px solid #ddd;padding:4px 8px}
+.content blockquote{margin:0;padding-left:16px;border-left:4px solid #ddd;color:#555}
+.cover{max-width:100%;margin-bottom:16px}
+.comments{margin-top:40px}.comment{padding:8px 0;border-bottom:1px solid #eee}
+.comment.reply{margin-left:32px}.comment p{margin:4px 0;white-space:pre-wrap}
+`
+
+// StylesheetName 是样式表的文件名
+const StylesheetName = "style.css"
+
+var funcs = template.FuncMap{
+	"date": func(t time.Time) string { return t.Format("2006-01-02") },
+}
+
+var pageTmpl = template.Must(template.New("page").Funcs(funcs).Parse(`<!DOCTYPE html>
+<html lang="zh-CN">
+<head>
+<meta charset="utf-8" />
+<meta name="viewport" content="width=device-width, initial-scale=1" />
+<title>{{.Title}}</title>
+{{- if .Description}}
+<meta name="description" content="{{.Description}}" />
+{{- end}}
+{{- if .Canonical}}
+<link rel="canonical" href="{{.Canonical}}" />
+{{- end}}
+<link rel="alternate" type="application/rss+xml" title="{{.Site.Title}}" href="{{.Root}}feed.xml" />
+<link rel="stylesheet" href="{{.Root}}style.css" />
+</head>
+<body>
+<header>
+<a class="site" href="{{.Root}}index.html">{{.Site.Title}}</a>
+{{- if .Site.Description}}
+<p>{{.Site.Description}}</p>
+{{- end}}
+<nav><a href="{{.Root}}index.html">首页</a><a href="{{.Root}}categories/index.html">分类</a><a href="{{.Root}}tags/index.html">标签</a><a href="{{.Root}}feed.xml">RSS</a></nav>
+</header>
+<main>
+{{- if .Article}}{{template "article" .}}{{else}}{{template "list" .}}{{end}}
+</main>
+<footer>&copy; {{.Site.Title}}</footer>
+</body>
+</html>
+`))
+
+func init() {
+	template.Must(pageTmpl.New("article").Parse(`
+{{- $root := .Root}}{{with .Article}}
+<article>
+<h1>{{$.Heading}}</h1>
+<p class="meta">{{date .Date}}{{if .Author}} · {{.Author}}{{end}}{{if .Category.Title}} · <a href="{{$root}}{{.Category.URL}}">{{.Category.Title}}</a>{{end}}</p>
+{{- if .Image}}
+<img class="cover" src="{{.Image}}" alt="" />
+{{- end}}
+<div class="content">
+{{.Content}}
+</div>
+{{- if .Tags}}
+<p class="meta">{{range .Tags}}<a class="tag" href="{{$root}}{{.URL}}">#{{.Title}}</a>{{end}}</p>
+{{- end}}
+{{- if ne (date .Updated) (date .Date)}}
+<p class="meta">更新于 {{date .Updated}}</p>
+{{- end}}
+</article>
+<div class="pager">
+<span>{{with .Prev}}&larr; <a href="{{$root}}{{.URL}}">{{.Title}}</a>{{end}}</span>
+<span>{{with .Next}}<a href="{{$root}}{{.URL}}">{{.Title}}</a> &rarr;{{end}}</span>
+</div>
+{{- if .Comments}}
+<section class="comments">
+<h2>评论 ({{len .Comments}})</h2>
+{{- range .Comments}}
+<div class="comment{{if .Reply}} reply{{end}}">
+<span class="meta">{{.Commentator}} · {{date .Date}}</span>
+<p>{{.Content}}</p>
+</div>
+{{- end}}
+</section>
+{{- end}}
+{{- end}}`))
+
+	template.Must(pageTmpl.New("list").Parse(`
+<h1>{{.Heading}}</h1>
+{{- range .Links}}
+<div class="entry"><a href="{{$.Root}}{{.URL}}">{{.Title}}</a>{{if .Count}} <span class="meta">({{.Count}})</span>{{end}}</div>
+{{- end}}
+{{- range .Articles}}
+<div class="entry">
+<h2>{{if .Pinned}}<span class="pinned">置顶</span>{{end}}<a href="{{$.Root}}{{.URL}}">{{.Title}}</a></h2>
+<p class="meta">{{date .Date}}{{if .Category.Title}} · <a href="{{$.Root}}{{.Category.URL}}">{{.Category.Title}}</a>{{end}}{{range .Tags}} <a class="tag" href="{{$.Root}}{{.URL}}">#{{.Title}}</a>{{end}}</p>
+{{- if .Summary}}
+<p>{{.Summary}}</p>
+{{- end}}
+</div>
+{{- end}}
+{{- if or .Prev .Next}}
+<div class="pager">
+<span>{{if .Prev}}<a href="{{.Root}}{{.Prev}}">&larr; 上一页</a>{{end}}</span>
+<span>{{if .Next}}<a href="{{.Root}}{{.Next}}">下一页 &rarr;</a>{{end}}</span>
+</div>
+{{- end}}`))
+}
+
+// Render 渲染一个页面
+func Render(w io.Writer, p *Page) error {
+	return pageTmpl.Execute(w, p)
+}
@@ -0,0 +1,68 @@
+// Package sitemap 按 sitemaps.org 协议渲染站点地图及站点地图索引
+package sitemap
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// MaxURLs 是单个站点地图允许包含的最大 URL 数
+const MaxURLs = 50000
+
+const (
+	ContentType = "application/xml; charset=utf-8"
+	namespace   = "http://www.sitemaps.org/schemas/sitemap/0.9"
+)
+
+// URL 是站点地图中的一个页面或索引中的一个站点地图
+type URL struct {
+	Loc     string
+	LastMod time.Time // 零值表示不输出
+}
+
+type urlSet struct {
+	XMLName xml.Name `xml:"urlset"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	URLs    []entry  `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name `xml:"sitemapindex"`
+	Xmlns    string   `xml:"xmlns,attr"`
+	Sitemaps []entry  `xml:"sitemap"`
+}
+
+type entry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+func entries(urls []URL) []entry {
+	result := make([]entry, 0, len(urls))
+	for _, u := range urls {
+		e := entry{Loc: u.Loc}
+		if !u.LastMod.IsZero() {
+			e.LastMod = u.LastMod.UTC().Format(time.RFC3339)
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+// URLSet 渲染包含页面列表的站点地图
+func URLSet(urls []URL) ([]byte, error) {
+	return marshal(urlSet{Xmlns: namespace, URLs: entries(urls)})
+}
+
+// Index 渲染站点地图索引
+func Index(sitemaps []URL) ([]byte, error) {
+	return marshal(sitemapIndex{Xmlns: namespace, Sitemaps: entries(sitemaps)})
+}
+
+func marshal(v any) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
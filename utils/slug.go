@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mozillazg/go-pinyin"
+)
+
+const slugMaxLength = 80
+
+var pinyinArgs = pinyin.NewArgs()
+
+// Slugify 将标题转换为 URL 友好的 slug：
+// 中文按拼音转写，字母转为小写，其余字符替换为连字符
+func Slugify(text string) string {
+	var b strings.Builder
+	dash := false
+	writePart := func(part string) {
+		if b.Len() > 0 && !dash {
+			b.WriteByte('-')
+		}
+		b.WriteString(part)
+		dash = false
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			if py := pinyin.SinglePinyin(r, pinyinArgs); len(py) > 0 {
+				writePart(py[0])
+				b.WriteByte('-')
+				dash = true
+			}
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(unicode.ToLower(r))
+			dash = false
+		default:
+			if b.Len() > 0 && !dash {
+				b.WriteByte('-')
+				dash = true
+			}
+		}
+	}
+
+	slug := strings.Trim(b.String(), "-")
+	if len(slug) > slugMaxLength {
+		slug = strings.TrimRight(slug[:slugMaxLength], "-")
+	}
+	return slug
+}
@@ -0,0 +1,465 @@
+// Package xmlrpc 实现 XML-RPC 的请求解析和响应编码，供 MetaWeblog 等桌面写作客户端使用的接口调用。
+//
+// 值与 Go 类型的对应关系: string、int (i4/int)、bool (boolean)、float64 (double)、
+// time.Time (dateTime.iso8601)、[]byte (base64)、[]any (array)、map[string]any (struct)
+package xmlrpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContentType 是 XML-RPC 响应的 Content-Type
+const ContentType = "text/xml; charset=utf-8"
+
+// 常见客户端发送的几种日期格式，均按 UTC 解析 (没有时区时)
+var dateLayouts = []string{
+	"20060102T15:04:05",
+	"20060102T15:04:05Z",
+	"20060102T15:04:05Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05Z07:00",
+	"20060102T150405",
+	"20060102T150405Z",
+}
+
+// Fault 是返回给客户端的错误
+type Fault struct {
+	Code    int
+	Message string
+}
+
+func (f *Fault) Error() string {
+	return fmt.Sprintf("%d: %s", f.Code, f.Message)
+}
+
+// 规范中常用的错误码
+const (
+	FaultParse          = -32700 // 请求不是合法的 XML
+	FaultInvalidRequest = -32600 // 请求结构不符合 XML-RPC
+	FaultMethodNotFound = -32601
+	FaultInvalidParams  = -32602
+	FaultInternal       = -32603
+)
+
+// ParseCall 解析 methodCall 请求，返回方法名和参数
+func ParseCall(r io.Reader) (string, []any, error) {
+	d := xml.NewDecoder(r)
+	d.Strict = false
+	d.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		return input, nil // 客户端几乎都使用 UTF-8，其余声明按 UTF-8 处理
+	}
+
+	var method string
+	var params []any
+	var seen bool
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", nil, &Fault{FaultParse, err.Error()}
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "methodCall":
+			seen = true
+		case "methodName":
+			if method, err = text(d); err != nil {
+				return "", nil, &Fault{FaultParse, err.Error()}
+			}
+			method = strings.TrimSpace(method)
+		case "value":
+			v, err := parseValue(d)
+			if err != nil {
+				return "", nil, &Fault{FaultInvalidRequest, err.Error()}
+			}
+			params = append(params, v)
+		}
+	}
+	if !seen || method == "" {
+		return "", nil, &Fault{FaultInvalidRequest, "缺少 methodCall 或 methodName"}
+	}
+	return method, params, nil
+}
+
+// text 读取元素中的文本直到元素结束
+func text(d *xml.Decoder) (string, error) {
+	var b strings.Builder
+	depth := 1
+	for depth > 0 {
+		tok, err := d.Token()
+		if err != nil {
+			return "", err
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			b.Write(t)
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+	return b.String(), nil
+}
+
+// parseValue 解析 <value> 元素，调用时 <value> 的开始标签已被读取
+func parseValue(d *xml.Decoder) (any, error) {
+	var raw strings.Builder
+	var result any
+	typed := false
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			raw.Write(t)
+		case xml.StartElement:
+			if typed {
+				if err := d.Skip(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			typed = true
+			if result, err = parseTyped(d, t.Name.Local); err != nil {
+				return nil, err
+			}
+		case xml.EndElement:
+			if !typed {
+				return raw.String(), nil // 没有类型标签时默认为字符串
+			}
+			return result, nil
+		}
+	}
+}
+
+// parseTyped 解析 <value> 中带类型的元素
+func parseTyped(d *xml.Decoder, kind string) (any, error) {
+	switch kind {
+	case "array":
+		return parseArray(d)
+	case "struct":
+		return parseStruct(d)
+	case "nil":
+		return nil, d.Skip()
+	}
+
+	s, err := text(d)
+	if err != nil {
+		return nil, err
+	}
+	switch kind {
+	case "string":
+		return s, nil
+	case "int", "i4", "i8":
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf("无效的整数 %q", s)
+		}
+		return n, nil
+	case "boolean":
+		switch strings.TrimSpace(s) {
+		case "1", "true":
+			return true, nil
+		case "0", "false":
+			return false, nil
+		}
+		return nil, fmt.Errorf("无效的布尔值 %q", s)
+	case "double":
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return nil, fmt.Errorf("无效的浮点数 %q", s)
+		}
+		return f, nil
+	case "dateTime.iso8601":
+		s = strings.TrimSpace(s)
+		for _, layout := range dateLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("无效的日期 %q", s)
+	case "base64":
+		data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(s), ""))
+		if err != nil {
+			return nil, fmt.Errorf("无效的 base64 数据: %v", err)
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("不支持的类型 %s", kind)
+}
+
+// parseArray 解析 <array><data><value>...</value></data></array>
+func parseArray(d *xml.Decoder) ([]any, error) {
+	items := []any{}
+	depth := 1
+	for depth > 0 {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "value" {
+				v, err := parseValue(d)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, v)
+				continue
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+	return items, nil
+}
+
+// parseStruct 解析 <struct><member><name/><value/></member>...</struct>
+func parseStruct(d *xml.Decoder) (map[string]any, error) {
+	members := map[string]any{}
+	var name string
+	depth := 1
+	for depth > 0 {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "name":
+				if name, err = text(d); err != nil {
+					return nil, err
+				}
+				name = strings.TrimSpace(name)
+			case "value":
+				v, err := parseValue(d)
+				if err != nil {
+					return nil, err
+				}
+				members[name] = v
+			default:
+				depth++
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+	return members, nil
+}
+
+// Response 将返回值编码为 methodResponse
+func Response(v any) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString("<methodResponse><params><param>")
+	if err := writeValue(&b, v); err != nil {
+		return nil, err
+	}
+	b.WriteString("</param></params></methodResponse>\n")
+	return b.Bytes(), nil
+}
+
+// FaultResponse 将错误编码为包含 fault 的 methodResponse
+func FaultResponse(f *Fault) []byte {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString("<methodResponse><fault>")
+	// 两个成员都是基本类型，不会编码失败
+	_ = writeValue(&b, map[string]any{"faultCode": f.Code, "faultString": f.Message})
+	b.WriteString("</fault></methodResponse>\n")
+	return b.Bytes()
+}
+
+// writeValue 将 Go 值编码为 <value> 元素，结构体的成员按名称排序输出
+func writeValue(b *bytes.Buffer, v any) error {
+	b.WriteString("<value>")
+	switch v := v.(type) {
+	case nil:
+		b.WriteString("<string></string>")
+	case string:
+		b.WriteString("<string>")
+		if err := xml.EscapeText(b, []byte(v)); err != nil {
+			return err
+		}
+		b.WriteString("</string>")
+	case bool:
+		if v {
+			b.WriteString("<boolean>1</boolean>")
+		} else {
+			b.WriteString("<boolean>0</boolean>")
+		}
+	case int, int64, int32, uint, uint64, uint32:
+		fmt.Fprintf(b, "<int>%d</int>", v)
+	case float64:
+		fmt.Fprintf(b, "<double>%s</double>", strconv.FormatFloat(v, 'f', -1, 64))
+	case time.Time:
+		fmt.Fprintf(b, "<dateTime.iso8601>%s</dateTime.iso8601>", v.UTC().Format("20060102T15:04:05Z"))
+	case []byte:
+		fmt.Fprintf(b, "<base64>%s</base64>", base64.StdEncoding.EncodeToString(v))
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.WriteString("<struct>")
+		for _, k := range keys {
+			b.WriteString("<member><name>")
+			if err := xml.EscapeText(b, []byte(k)); err != nil {
+				return err
+			}
+			b.WriteString("</name>")
+			if err := writeValue(b, v[k]); err != nil {
+				return err
+			}
+			b.WriteString("</member>")
+		}
+		b.WriteString("</struct>")
+	default:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice {
+			return fmt.Errorf("xmlrpc: 不支持的类型 %T", v)
+		}
+		b.WriteString("<array><data>")
+		for i := 0; i < rv.Len(); i++ {
+			if err := writeValue(b, rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		b.WriteString("</data></array>")
+	}
+	b.WriteString("</value>")
+	return nil
+}
+
+// Args 按位置读取调用参数，类型不符时记录第一个错误
+type Args struct {
+	params []any
+	err    error
+}
+
+// NewArgs 包装调用参数
+func NewArgs(params []any) *Args {
+	return &Args{params: params}
+}
+
+// Err 返回读取参数时的第一个错误
+func (a *Args) Err() error {
+	return a.err
+}
+
+func (a *Args) fail(i int, want string) {
+	if a.err == nil {
+		a.err = &Fault{FaultInvalidParams, fmt.Sprintf("第 %d 个参数应为 %s", i+1, want)}
+	}
+}
+
+// String 读取字符串参数，客户端有时以整数传递 ID，也按字符串返回
+func (a *Args) String(i int) string {
+	if i >= len(a.params) {
+		a.fail(i, "string")
+		return ""
+	}
+	switch v := a.params[i].(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	}
+	a.fail(i, "string")
+	return ""
+}
+
+// Int 读取整数参数，缺省时返回 def
+func (a *Args) Int(i int, def int) int {
+	if i >= len(a.params) {
+		return def
+	}
+	switch v := a.params[i].(type) {
+	case int:
+		return v
+	case string:
+		if n, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			return n
+		}
+	}
+	a.fail(i, "int")
+	return def
+}
+
+// Bool 读取布尔参数，缺省时返回 def
+func (a *Args) Bool(i int, def bool) bool {
+	if i >= len(a.params) {
+		return def
+	}
+	switch v := a.params[i].(type) {
+	case bool:
+		return v
+	case int:
+		return v != 0
+	}
+	a.fail(i, "boolean")
+	return def
+}
+
+// Struct 读取结构体参数
+func (a *Args) Struct(i int) map[string]any {
+	if i < len(a.params) {
+		if v, ok := a.params[i].(map[string]any); ok {
+			return v
+		}
+	}
+	a.fail(i, "struct")
+	return map[string]any{}
+}
+
+// Member 读取结构体中的字符串成员，ok 表示成员存在
+func Member(m map[string]any, key string) (string, bool) {
+	switch v := m[key].(type) {
+	case string:
+		return v, true
+	case int:
+		return strconv.Itoa(v), true
+	}
+	return "", false
+}
+
+// Members 读取结构体中的字符串数组成员
+func Members(m map[string]any, key string) ([]string, bool) {
+	items, ok := m[key].([]any)
+	if !ok {
+		return nil, false
+	}
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result, true
+}
+
+// TimeMember 读取结构体中的日期成员
+func TimeMember(m map[string]any, key string) (time.Time, bool) {
+	t, ok := m[key].(time.Time)
+	return t, ok
+}
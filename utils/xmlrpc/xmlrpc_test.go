@@ -0,0 +1,333 @@
+package xmlrpc
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// call 将参数片段包装为 methodCall 请求
+func call(method string, params ...string) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0"?><methodCall><methodName>` + method + `</methodName><params>`)
+	for _, p := range params {
+		b.WriteString("<param>" + p + "</param>")
+	}
+	b.WriteString("</params></methodCall>")
+	return b.String()
+}
+
+func TestParseCall(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		method string
+		params []any
+	}{
+		{
+			name:   "无参数",
+			input:  call("blogger.getUsersBlogs"),
+			method: "blogger.getUsersBlogs",
+		},
+		{
+			name:   "方法名两侧的空白",
+			input:  `<methodCall><methodName> metaWeblog.getPost </methodName></methodCall>`,
+			method: "metaWeblog.getPost",
+		},
+		{
+			name: "基本类型",
+			input: call("test",
+				`<value><string>a &amp; b</string></value>`,
+				`<value>untyped</value>`,
+				`<value><int>42</int></value>`,
+				`<value><i4>-7</i4></value>`,
+				`<value><i8> 9 </i8></value>`,
+				`<value><boolean>1</boolean></value>`,
+				`<value><boolean>false</boolean></value>`,
+				`<value><double>1.5</double></value>`,
+				`<value><base64>aGVs
+				bG8=</base64></value>`,
+				`<value><nil/></value>`,
+			),
+			method: "test",
+			params: []any{"a & b", "untyped", 42, -7, 9, true, false, 1.5, []byte("hello"), nil},
+		},
+		{
+			name: "嵌套的结构体和数组",
+			input: call("metaWeblog.newPost",
+				`<value><string>1</string></value>`,
+				`<value><struct>
+					<member><name>title</name><value><string>标题</string></value></member>
+					<member><name>categories</name><value><array><data>
+						<value><string>Go</string></value>
+						<value>笔记</value>
+					</data></array></value></member>
+					<member><name>custom</name><value><struct>
+						<member><name>nested</name><value><array><data>
+							<value><array><data><value><int>1</int></value></data></array></value>
+							<value><struct></struct></value>
+						</data></array></value></member>
+					</struct></value></member>
+				</struct></value>`,
+				`<value><boolean>1</boolean></value>`,
+			),
+			method: "metaWeblog.newPost",
+			params: []any{
+				"1",
+				map[string]any{
+					"title":      "标题",
+					"categories": []any{"Go", "笔记"},
+					"custom": map[string]any{
+						"nested": []any{[]any{1}, map[string]any{}},
+					},
+				},
+				true,
+			},
+		},
+		{
+			name:   "空数组",
+			input:  call("test", `<value><array><data></data></array></value>`),
+			method: "test",
+			params: []any{[]any{}},
+		},
+		{
+			name:   "类型标签后多余的元素被忽略",
+			input:  call("test", `<value><int>1</int><string>x</string></value>`),
+			method: "test",
+			params: []any{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method, params, err := ParseCall(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("ParseCall() error = %v", err)
+			}
+			if method != tt.method {
+				t.Errorf("method = %q, want %q", method, tt.method)
+			}
+			if !reflect.DeepEqual(params, tt.params) {
+				t.Errorf("params = %#v, want %#v", params, tt.params)
+			}
+		})
+	}
+}
+
+func TestParseCallDates(t *testing.T) {
+	want := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []string{
+		"20240102T03:04:05",
+		"20240102T03:04:05Z",
+		"20240102T11:04:05+08:00",
+		"2024-01-02T03:04:05",
+		"2024-01-02T11:04:05+08:00",
+		"20240102T030405",
+		" 20240102T030405Z ",
+	}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			_, params, err := ParseCall(strings.NewReader(call("test", "<value><dateTime.iso8601>"+input+"</dateTime.iso8601></value>")))
+			if err != nil {
+				t.Fatalf("ParseCall() error = %v", err)
+			}
+			got, ok := params[0].(time.Time)
+			if !ok || !got.Equal(want) {
+				t.Errorf("date = %#v, want %v", params[0], want)
+			}
+		})
+	}
+}
+
+func TestParseCallMalformed(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		code  int
+	}{
+		{"空请求", "", FaultInvalidRequest},
+		{"不是 XML", "garbage", FaultInvalidRequest},
+		{"缺少 methodCall", `<methodName>test</methodName>`, FaultInvalidRequest},
+		{"缺少 methodName", `<methodCall><params></params></methodCall>`, FaultInvalidRequest},
+		{"空的 methodName", `<methodCall><methodName> </methodName></methodCall>`, FaultInvalidRequest},
+		{"无效的整数", call("test", `<value><int>abc</int></value>`), FaultInvalidRequest},
+		{"无效的布尔值", call("test", `<value><boolean>yes</boolean></value>`), FaultInvalidRequest},
+		{"无效的浮点数", call("test", `<value><double>1.2.3</double></value>`), FaultInvalidRequest},
+		{"无效的日期", call("test", `<value><dateTime.iso8601>2024/01/02</dateTime.iso8601></value>`), FaultInvalidRequest},
+		{"无效的 base64", call("test", `<value><base64>!!!</base64></value>`), FaultInvalidRequest},
+		{"不支持的类型", call("test", `<value><float>1</float></value>`), FaultInvalidRequest},
+		{"嵌套值中的错误", call("test", `<value><struct><member><name>a</name><value><array><data><value><int>x</int></value></data></array></value></member></struct></value>`), FaultInvalidRequest},
+		{"未闭合的值", `<methodCall><methodName>test</methodName><params><param><value><string>abc`, FaultInvalidRequest},
+		{"未闭合的方法名", `<methodCall><methodName>test`, FaultParse},
+		{"非法的标签", `<methodCall><methodName>test</methodName></methodCall><`, FaultParse},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ParseCall(strings.NewReader(tt.input))
+			var fault *Fault
+			if !errors.As(err, &fault) {
+				t.Fatalf("ParseCall() error = %v, want *Fault", err)
+			}
+			if fault.Code != tt.code {
+				t.Errorf("fault code = %d, want %d (%s)", fault.Code, tt.code, fault.Message)
+			}
+		})
+	}
+}
+
+func TestResponse(t *testing.T) {
+	when := time.Date(2024, 1, 2, 11, 4, 5, 0, time.FixedZone("CST", 8*3600))
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"字符串转义", "<a & b>", `<value><string>&lt;a &amp; b&gt;</string></value>`},
+		{"nil", nil, `<value><string></string></value>`},
+		{"布尔值", true, `<value><boolean>1</boolean></value>`},
+		{"整数", 42, `<value><int>42</int></value>`},
+		{"无符号整数", uint(7), `<value><int>7</int></value>`},
+		{"浮点数", 1.5, `<value><double>1.5</double></value>`},
+		{"日期转换为 UTC", when, `<value><dateTime.iso8601>20240102T03:04:05Z</dateTime.iso8601></value>`},
+		{"base64", []byte("hello"), `<value><base64>aGVsbG8=</base64></value>`},
+		{
+			"结构体成员按名称排序",
+			map[string]any{"b": 1, "a": "x"},
+			`<value><struct><member><name>a</name><value><string>x</string></value></member><member><name>b</name><value><int>1</int></value></member></struct></value>`,
+		},
+		{
+			"任意类型的切片",
+			[]map[string]any{{"id": "1"}},
+			`<value><array><data><value><struct><member><name>id</name><value><string>1</string></value></member></struct></value></data></array></value>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Response(tt.value)
+			if err != nil {
+				t.Fatalf("Response() error = %v", err)
+			}
+			want := "<methodResponse><params><param>" + tt.want + "</param></params></methodResponse>"
+			if !bytes.Contains(got, []byte(want)) {
+				t.Errorf("Response() = %s, want %s", got, want)
+			}
+		})
+	}
+
+	if _, err := Response(struct{}{}); err == nil {
+		t.Error("Response(struct{}{}) error = nil, want error")
+	}
+}
+
+func TestResponseRoundTrip(t *testing.T) {
+	value := map[string]any{
+		"title":       "<标题>",
+		"categories":  []any{"Go", "笔记"},
+		"dateCreated": time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		"postid":      12,
+		"publish":     true,
+		"bits":        []byte{0, 1, 2},
+	}
+	resp, err := Response(value)
+	if err != nil {
+		t.Fatalf("Response() error = %v", err)
+	}
+	// 将响应改写为请求，再由 ParseCall 解析
+	req := strings.Replace(string(resp), "<methodResponse>", "<methodCall><methodName>echo</methodName>", 1)
+	req = strings.Replace(req, "</methodResponse>", "</methodCall>", 1)
+	_, params, err := ParseCall(strings.NewReader(req))
+	if err != nil {
+		t.Fatalf("ParseCall() error = %v", err)
+	}
+	if len(params) != 1 || !reflect.DeepEqual(params[0], value) {
+		t.Errorf("round trip = %#v, want %#v", params, value)
+	}
+}
+
+func TestFaultResponse(t *testing.T) {
+	got := string(FaultResponse(&Fault{FaultInvalidParams, "参数 <错误>"}))
+	want := `<methodResponse><fault><value><struct>` +
+		`<member><name>faultCode</name><value><int>-32602</int></value></member>` +
+		`<member><name>faultString</name><value><string>参数 &lt;错误&gt;</string></value></member>` +
+		`</struct></value></fault></methodResponse>`
+	if !strings.Contains(got, want) {
+		t.Errorf("FaultResponse() = %s, want %s", got, want)
+	}
+}
+
+func TestArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  []any
+		read    func(a *Args) any
+		want    any
+		wantErr bool
+	}{
+		{"字符串", []any{"abc"}, func(a *Args) any { return a.String(0) }, "abc", false},
+		{"整数作为字符串", []any{12}, func(a *Args) any { return a.String(0) }, "12", false},
+		{"缺少字符串", nil, func(a *Args) any { return a.String(0) }, "", true},
+		{"字符串类型不符", []any{true}, func(a *Args) any { return a.String(0) }, "", true},
+		{"整数", []any{3}, func(a *Args) any { return a.Int(0, 10) }, 3, false},
+		{"字符串作为整数", []any{" 5 "}, func(a *Args) any { return a.Int(0, 10) }, 5, false},
+		{"缺省的整数", nil, func(a *Args) any { return a.Int(0, 10) }, 10, false},
+		{"无效的整数", []any{"x"}, func(a *Args) any { return a.Int(0, 10) }, 10, true},
+		{"布尔值", []any{true}, func(a *Args) any { return a.Bool(0, false) }, true, false},
+		{"整数作为布尔值", []any{0}, func(a *Args) any { return a.Bool(0, true) }, false, false},
+		{"缺省的布尔值", nil, func(a *Args) any { return a.Bool(0, true) }, true, false},
+		{"无效的布尔值", []any{"1"}, func(a *Args) any { return a.Bool(0, false) }, false, true},
+		{"结构体", []any{map[string]any{"a": 1}}, func(a *Args) any { return a.Struct(0) }, map[string]any{"a": 1}, false},
+		{"缺少结构体", nil, func(a *Args) any { return a.Struct(0) }, map[string]any{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewArgs(tt.params)
+			if got := tt.read(a); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+			err := a.Err()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Err() = %v, wantErr %v", err, tt.wantErr)
+			}
+			var fault *Fault
+			if err != nil && (!errors.As(err, &fault) || fault.Code != FaultInvalidParams) {
+				t.Errorf("Err() = %v, want FaultInvalidParams", err)
+			}
+		})
+	}
+}
+
+func TestMembers(t *testing.T) {
+	when := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	m := map[string]any{
+		"title":      "标题",
+		"postid":     7,
+		"categories": []any{"Go", 1, "笔记"},
+		"date":       when,
+	}
+
+	if s, ok := Member(m, "title"); !ok || s != "标题" {
+		t.Errorf("Member(title) = %q, %v", s, ok)
+	}
+	if s, ok := Member(m, "postid"); !ok || s != "7" {
+		t.Errorf("Member(postid) = %q, %v", s, ok)
+	}
+	if _, ok := Member(m, "missing"); ok {
+		t.Error("Member(missing) ok = true")
+	}
+	if names, ok := Members(m, "categories"); !ok || !reflect.DeepEqual(names, []string{"Go", "笔记"}) {
+		t.Errorf("Members(categories) = %v, %v", names, ok)
+	}
+	if _, ok := Members(m, "title"); ok {
+		t.Error("Members(title) ok = true")
+	}
+	if d, ok := TimeMember(m, "date"); !ok || !d.Equal(when) {
+		t.Errorf("TimeMember(date) = %v, %v", d, ok)
+	}
+}